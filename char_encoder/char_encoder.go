@@ -1,10 +1,27 @@
 package char_encoder
 
-import "errors"
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
 
 const END_OF_STRING uint16 = 0xFFFF
 const NULL_CHAR uint16 = 0x0
 
+// first index of the half-width characters used by western releases
+const WESTERN_CHARS_START uint16 = 0x121
+
+// single characters drawn as "PK" and "MN", as in the POKéMON logo
+const PK_LIGATURE uint16 = 0x1E0
+const MN_LIGATURE uint16 = 0x1E1
+
+// tokens standing in for the ligatures, since no rune maps to them
+const PK_TOKEN = "{PK}"
+const MN_TOKEN = "{MN}"
+
 var chars [496]string = [496]string{"␀", "　", "ぁ", "あ", "ぃ", "い", "ぅ", "う", "ぇ", "え", "ぉ", "お", "か", "が", "き", "ぎ", "く", "ぐ", "け", "げ", "こ", "ご", "さ", "ざ", "し", "じ", "す", "ず", "せ", "ぜ", "そ", "ぞ", "た", "だ", "ち", "ぢ", "っ", "つ", "づ", "て", "で", "と", "ど", "な", "に", "ぬ", "ね", "の", "は", "ば", "ぱ", "ひ", "び", "ぴ", "ふ", "ぶ", "ぷ", "へ", "べ", "ぺ", "ほ", "ぼ", "ぽ", "ま", "み", "む", "め", "も", "ゃ", "や", "ゅ", "ゆ", "ょ", "よ", "ら", "り", "る", "れ", "ろ", "わ", "を", "ん", "ァ", "ア", "ィ", "イ", "ゥ", "ウ", "ェ", "エ", "ォ", "オ", "カ", "ガ", "キ", "ギ", "ク", "グ", "ケ", "ゲ", "コ", "ゴ", "サ", "ザ", "シ", "ジ", "ス", "ズ", "セ", "ゼ", "ソ", "ゾ", "タ", "ダ", "チ", "ヂ", "ッ", "ツ", "ヅ", "テ", "デ", "ト", "ド", "ナ", "ニ", "ヌ", "ネ", "ノ", "ハ", "バ", "パ", "ヒ", "ビ", "ピ", "フ", "ブ", "プ", "ヘ", "ベ", "ペ", "ホ", "ボ", "ポ", "マ", "ミ", "ム", "メ", "モ", "ャ", "ヤ", "ュ", "ユ", "ョ", "ヨ", "ラ", "リ", "ル", "レ", "ロ", "ワ", "ヲ", "ン", "０", "１", "２", "３", "４", "５", "６", "７", "８", "９", "Ａ", "Ｂ", "Ｃ", "Ｄ", "Ｅ", "Ｆ", "Ｇ", "Ｈ", "Ｉ", "Ｊ", "Ｋ", "Ｌ", "Ｍ", "Ｎ", "Ｏ", "Ｐ", "Ｑ", "Ｒ", "Ｓ", "Ｔ", "Ｕ", "Ｖ", "Ｗ", "Ｘ", "Ｙ", "Ｚ", "ａ", "ｂ", "ｃ", "ｄ", "ｅ", "ｆ", "ｇ", "ｈ", "ｉ", "ｊ", "ｋ", "ｌ", "ｍ", "ｎ", "ｏ", "ｐ", "ｑ", "ｒ", "ｓ", "ｔ", "ｕ", "ｖ", "ｗ", "ｘ", "ｙ", "ｚ", "", "！", "？", "、", "。", "…", "・", "／", "「", "」", "『", "』", "（", "）", "♂", "♀", "＋", "ー", "×", "÷", "＝", "～", "：", "；", "．", "，", "♠", "♣", "♥", "♦", "★", "◎", "○", "□", "△", "◇", "＠", "♪", "％", "☀", "☁", "☂", "☃", "", "", "", "", "", "", "", "円", "", "", "", "", "", "", "", "", "←", "↑", "↓", "→", "►", "＆", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z", "a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y", "z", "À", "Á", "Â", "Ã", "Ä", "Å", "Æ", "Ç", "È", "É", "Ê", "Ë", "Ì", "Í", "Î", "Ï", "Ð", "Ñ", "Ò", "Ó", "Ô", "Õ", "Ö", "×", "Ø", "Ù", "Ú", "Û", "Ü", "Ý", "Þ", "ß", "à", "á", "â", "ã", "ä", "å", "æ", "ç", "è", "é", "ê", "ë", "ì", "í", "î", "ï", "ð", "ñ", "ò", "ó", "ô", "õ", "ö", "÷", "ø", "ù", "ú", "û", "ü", "ý", "þ", "ÿ", "Œ", "œ", "Ş", "ş", "ª", "º", "er", "re", "r", "", "¡", "¿", "!", "?", ",", ".", "…", "･", "/", "‘", "’", "“", "”", "„", "«", "»", "(", ")", "♂", "♀", "+", "-", "*", "#", "=", "&", "~", ":", ";", "♠", "♣", "♥", "♦", "★", "◎", "○", "□", "△", "◇", "@", "♪", "%", "☀", "☁", "☂", "☃", "", "", "", "", "", "", "", " ", "e", "PK", "MN", " ", " ", " ", " ", " ", " ", "°", "_", "＿", "․", "‥", "", "", ""}
 
 func Char(index uint16) (string, error) {
@@ -15,3 +32,129 @@ func Char(index uint16) (string, error) {
 
 	return chars[index], nil
 }
+
+// reverse lookup for Encode. Characters that appear more than once map to
+// their half-width (western) index. Multi-character ligatures like "PK" and
+// "MN" are left out so regular text is never collapsed into them; they are
+// only encoded from PK_TOKEN and MN_TOKEN
+var codes map[rune]uint16 = buildCodes()
+
+func buildCodes() map[rune]uint16 {
+	res := make(map[rune]uint16)
+
+	for i, c := range chars {
+		index := uint16(i)
+		runes := []rune(c)
+		if index == NULL_CHAR || len(runes) != 1 {
+			continue
+		}
+
+		existing, ok := res[runes[0]]
+		if !ok || (existing < WESTERN_CHARS_START && index >= WESTERN_CHARS_START) {
+			res[runes[0]] = index
+		}
+	}
+
+	return res
+}
+
+// Converts `s` to Gen IV character indices, followed by END_OF_STRING.
+// PK_TOKEN and MN_TOKEN are encoded as the PK and MN ligatures
+func Encode(s string) ([]uint16, error) {
+	var res []uint16
+
+	for len(s) > 0 {
+		if rest, ok := strings.CutPrefix(s, PK_TOKEN); ok {
+			res, s = append(res, PK_LIGATURE), rest
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(s, MN_TOKEN); ok {
+			res, s = append(res, MN_LIGATURE), rest
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s)
+		index, ok := codes[r]
+		if !ok {
+			return nil, fmt.Errorf("character '%c' can't be encoded", r)
+		}
+		res, s = append(res, index), s[size:]
+	}
+
+	return append(res, END_OF_STRING), nil
+}
+
+// Same as Encode, padded with END_OF_STRING to exactly `length` indices.
+// The terminator counts towards `length`
+func EncodePadded(s string, length int) ([]uint16, error) {
+	res, err := Encode(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res) > length {
+		return nil, fmt.Errorf("'%s' does not fit in %d characters", s, length-1)
+	}
+
+	for len(res) < length {
+		res = append(res, END_OF_STRING)
+	}
+
+	return res, nil
+}
+
+// Converts Gen IV character indices to a string, stopping at the first
+// terminator. Indices without a mapping are replaced with U+FFFD, and the
+// PK and MN ligatures with PK_TOKEN and MN_TOKEN so the result encodes back
+// to the same indices
+func DecodeString(indices []uint16) string {
+	res := ""
+
+	for _, index := range indices {
+		if index == END_OF_STRING || index == NULL_CHAR {
+			break
+		}
+
+		switch index {
+		case PK_LIGATURE:
+			res += PK_TOKEN
+			continue
+		case MN_LIGATURE:
+			res += MN_TOKEN
+			continue
+		}
+
+		str, err := Char(index)
+		if err != nil {
+			str = "\uFFFD"
+		}
+		res += str
+	}
+
+	return res
+}
+
+// Same as DecodeString, reading little endian indices from `buf`
+func DecodeBytes(buf []byte) string {
+	indices := make([]uint16, len(buf)/2)
+	for i := range indices {
+		indices[i] = binary.LittleEndian.Uint16(buf[2*i : 2*i+2])
+	}
+
+	return DecodeString(indices)
+}
+
+// Encodes `s` into `buf` as little endian indices, padding the rest of `buf`
+func EncodeBytes(s string, buf []byte) error {
+	indices, err := EncodePadded(s, len(buf)/2)
+	if err != nil {
+		return err
+	}
+
+	for i, index := range indices {
+		binary.LittleEndian.PutUint16(buf[2*i:2*i+2], index)
+	}
+
+	return nil
+}
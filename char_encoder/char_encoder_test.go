@@ -1,6 +1,9 @@
 package char_encoder
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestCharOutOfBoundsIndex(t *testing.T) {
 	_, err := Char(1000)
@@ -37,3 +40,112 @@ func TestCharValidChars(t *testing.T) {
 		t.Fatal("Incorrect character received")
 	}
 }
+
+func TestEncode(t *testing.T) {
+	indices, err := Encode("Dd 0!")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	expected := []uint16{0x012E, 0x0148, 0x01DE, 0x0121, 0x01AB, END_OF_STRING}
+	if !slices.Equal(indices, expected) {
+		t.Fatalf("expected %x, got %x", expected, indices)
+	}
+}
+
+func TestEncodePrefersHalfWidth(t *testing.T) {
+	indices, err := Encode("♂×")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	expected := []uint16{0x01BB, 0x0176, END_OF_STRING}
+	if !slices.Equal(indices, expected) {
+		t.Fatalf("expected %x, got %x", expected, indices)
+	}
+}
+
+func TestEncodeIgnoresLigatures(t *testing.T) {
+	indices, err := Encode("PKMN")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if len(indices) != 5 {
+		t.Fatalf("expected 4 characters and a terminator, got %x", indices)
+	}
+}
+
+func TestEncodeLigatureTokens(t *testing.T) {
+	indices, err := Encode("{PK}{MN} D")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	expected := []uint16{PK_LIGATURE, MN_LIGATURE, 0x01DE, 0x012E, END_OF_STRING}
+	if !slices.Equal(indices, expected) {
+		t.Fatalf("expected %x, got %x", expected, indices)
+	}
+
+	if decoded := DecodeString(indices); decoded != "{PK}{MN} D" {
+		t.Fatalf("expected '{PK}{MN} D', got '%s'", decoded)
+	}
+
+	// a lone brace is not a token, and can't be encoded
+	if _, err := Encode("{PK"); err == nil {
+		t.Fatal("Error not thrown for an incomplete token")
+	}
+}
+
+func TestEncodeUnknownCharacter(t *testing.T) {
+	if _, err := Encode("日本"); err == nil {
+		t.Fatal("Error not thrown for characters without a mapping")
+	}
+}
+
+func TestEncodePadded(t *testing.T) {
+	indices, err := EncodePadded("AB", 5)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	expected := []uint16{0x012B, 0x012C, END_OF_STRING, END_OF_STRING, END_OF_STRING}
+	if !slices.Equal(indices, expected) {
+		t.Fatalf("expected %x, got %x", expected, indices)
+	}
+
+	if _, err := EncodePadded("ABCDE", 5); err == nil {
+		t.Fatal("Error not thrown for a string without room for its terminator")
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	decoded := DecodeString([]uint16{0x012E, 0x01E0, 0x01E1, END_OF_STRING, 0x012B})
+	if decoded != "D{PK}{MN}" {
+		t.Fatalf("expected 'D{PK}{MN}', got '%s'", decoded)
+	}
+}
+
+func TestDecodeEncodeRoundTrip(t *testing.T) {
+	original := []uint16{0x012E, PK_LIGATURE, MN_LIGATURE, 0x01DE, 0x0121, END_OF_STRING}
+	indices, err := Encode(DecodeString(original))
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if !slices.Equal(indices, original) {
+		t.Fatalf("expected %x, got %x", original, indices)
+	}
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	original := "Pokémon Platinum!"
+	indices, err := Encode(original)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if decoded := DecodeString(indices); decoded != original {
+		t.Fatalf("expected '%s', got '%s'", original, decoded)
+	}
+}
@@ -0,0 +1,87 @@
+package data
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+type BaseStats struct {
+	Hp        uint8
+	Attack    uint8
+	Defense   uint8
+	SpAttack  uint8
+	SpDefense uint8
+	Speed     uint8
+}
+
+func (b BaseStats) Total() uint {
+	return uint(b.Hp) + uint(b.Attack) + uint(b.Defense) +
+		uint(b.SpAttack) + uint(b.SpDefense) + uint(b.Speed)
+}
+
+// generation 4 base stats, one species per line in the order
+// HP, Atk, Def, SpA, SpD, Spe. Values predate the generation 6 buffs
+//
+//go:embed base_stats.txt
+var baseStatsFile string
+
+var baseStats = parseBaseStats(baseStatsFile)
+
+// index 0 is left empty so that national dex numbers can be used directly
+func parseBaseStats(file string) []BaseStats {
+	lines := strings.Split(strings.TrimRight(file, "\n"), "\n")
+	res := make([]BaseStats, 1, len(lines)+1)
+
+	for i, line := range lines {
+		var b BaseStats
+		_, err := fmt.Sscan(line, &b.Hp, &b.Attack, &b.Defense, &b.SpAttack, &b.SpDefense, &b.Speed)
+		if err != nil {
+			panic(fmt.Sprintf("base_stats.txt line %d: %s", i+1, err))
+		}
+		res = append(res, b)
+	}
+
+	return res
+}
+
+// alternate forms whose stats differ from the species' default form,
+// indexed by the form stored in block B
+var formBaseStats = map[uint16]map[uint8]BaseStats{
+	386: { // deoxys
+		1: {50, 180, 20, 180, 20, 150},
+		2: {50, 70, 160, 70, 160, 90},
+		3: {50, 95, 90, 95, 90, 180},
+	},
+	413: { // wormadam
+		1: {60, 79, 105, 59, 85, 36},
+		2: {60, 69, 95, 69, 95, 36},
+	},
+	479: { // rotom
+		1: {50, 65, 107, 105, 107, 86},
+		2: {50, 65, 107, 105, 107, 86},
+		3: {50, 65, 107, 105, 107, 86},
+		4: {50, 65, 107, 105, 107, 86},
+		5: {50, 65, 107, 105, 107, 86},
+	},
+	487: { // giratina
+		1: {150, 120, 100, 120, 100, 90},
+	},
+	492: { // shaymin
+		1: {100, 103, 75, 120, 75, 127},
+	},
+}
+
+// Returns the base stats of `species` in the given form.
+// ok is false for species outside of generation 4
+func BaseStatsOf(species uint16, form uint8) (stats BaseStats, ok bool) {
+	if species == 0 || species > SPECIES_COUNT {
+		return BaseStats{}, false
+	}
+
+	if stats, found := formBaseStats[species][form]; found {
+		return stats, true
+	}
+
+	return baseStats[species], true
+}
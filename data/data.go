@@ -0,0 +1,152 @@
+package data
+
+// highest national dex number in generation 4
+const SPECIES_COUNT = 493
+
+// Threshold compared against the low byte of the personality value:
+// a pokemon is female when that byte is below the species' ratio.
+// Single-gender and genderless species ignore the personality value
+type GenderRatio uint8
+
+const (
+	RatioMaleOnly   GenderRatio = 0
+	RatioMale7To1   GenderRatio = 31
+	RatioMale3To1   GenderRatio = 63
+	RatioEven       GenderRatio = 127
+	RatioFemale3To1 GenderRatio = 191
+	RatioFemale7To1 GenderRatio = 225
+	RatioFemaleOnly GenderRatio = 254
+	RatioGenderless GenderRatio = 255
+)
+
+func (r GenderRatio) String() string {
+	switch r {
+	case RatioMaleOnly:
+		return "Male only"
+	case RatioMale7To1:
+		return "7M:1F"
+	case RatioMale3To1:
+		return "3M:1F"
+	case RatioEven:
+		return "1M:1F"
+	case RatioFemale3To1:
+		return "1M:3F"
+	case RatioFemale7To1:
+		return "1M:7F"
+	case RatioFemaleOnly:
+		return "Female only"
+	case RatioGenderless:
+		return "Genderless"
+	default:
+		return "Unknown"
+	}
+}
+
+// species whose gender ratio isn't 1:1, by national dex number
+var genderRatios = map[uint16]GenderRatio{
+	// starters
+	1: RatioMale7To1, 2: RatioMale7To1, 3: RatioMale7To1,
+	4: RatioMale7To1, 5: RatioMale7To1, 6: RatioMale7To1,
+	7: RatioMale7To1, 8: RatioMale7To1, 9: RatioMale7To1,
+	152: RatioMale7To1, 153: RatioMale7To1, 154: RatioMale7To1,
+	155: RatioMale7To1, 156: RatioMale7To1, 157: RatioMale7To1,
+	158: RatioMale7To1, 159: RatioMale7To1, 160: RatioMale7To1,
+	252: RatioMale7To1, 253: RatioMale7To1, 254: RatioMale7To1,
+	255: RatioMale7To1, 256: RatioMale7To1, 257: RatioMale7To1,
+	258: RatioMale7To1, 259: RatioMale7To1, 260: RatioMale7To1,
+	387: RatioMale7To1, 388: RatioMale7To1, 389: RatioMale7To1,
+	390: RatioMale7To1, 391: RatioMale7To1, 392: RatioMale7To1,
+	393: RatioMale7To1, 394: RatioMale7To1, 395: RatioMale7To1,
+
+	// eevee and its evolutions
+	133: RatioMale7To1, 134: RatioMale7To1, 135: RatioMale7To1, 136: RatioMale7To1,
+	196: RatioMale7To1, 197: RatioMale7To1, 470: RatioMale7To1, 471: RatioMale7To1,
+
+	// fossils
+	138: RatioMale7To1, 139: RatioMale7To1, 140: RatioMale7To1, 141: RatioMale7To1,
+	142: RatioMale7To1, 345: RatioMale7To1, 346: RatioMale7To1, 347: RatioMale7To1,
+	348: RatioMale7To1, 408: RatioMale7To1, 409: RatioMale7To1, 410: RatioMale7To1,
+	411: RatioMale7To1,
+
+	143: RatioMale7To1, 446: RatioMale7To1, // snorlax, munchlax
+	175: RatioMale7To1, 176: RatioMale7To1, 468: RatioMale7To1, // togepi line
+	415: RatioMale7To1,                     // combee
+	447: RatioMale7To1, 448: RatioMale7To1, // riolu, lucario
+
+	58: RatioMale3To1, 59: RatioMale3To1, // growlithe, arcanine
+	63: RatioMale3To1, 64: RatioMale3To1, 65: RatioMale3To1, // abra line
+	66: RatioMale3To1, 67: RatioMale3To1, 68: RatioMale3To1, // machop line
+	125: RatioMale3To1, 239: RatioMale3To1, 466: RatioMale3To1, // electabuzz line
+	126: RatioMale3To1, 240: RatioMale3To1, 467: RatioMale3To1, // magmar line
+	296: RatioMale3To1, 297: RatioMale3To1, // makuhita, hariyama
+
+	35: RatioFemale3To1, 36: RatioFemale3To1, 173: RatioFemale3To1, // clefairy line
+	37: RatioFemale3To1, 38: RatioFemale3To1, // vulpix, ninetales
+	39: RatioFemale3To1, 40: RatioFemale3To1, 174: RatioFemale3To1, // jigglypuff line
+	209: RatioFemale3To1, 210: RatioFemale3To1, // snubbull, granbull
+	222: RatioFemale3To1,                       // corsola
+	298: RatioFemale3To1,                       // azurill
+	300: RatioFemale3To1, 301: RatioFemale3To1, // skitty, delcatty
+	370: RatioFemale3To1,                       // luvdisc
+	431: RatioFemale3To1, 432: RatioFemale3To1, // glameow, purugly
+
+	32: RatioMaleOnly, 33: RatioMaleOnly, 34: RatioMaleOnly, // nidoran♂ line
+	106: RatioMaleOnly, 107: RatioMaleOnly, 236: RatioMaleOnly, 237: RatioMaleOnly, // tyrogue line
+	128: RatioMaleOnly, // tauros
+	313: RatioMaleOnly, // volbeat
+	381: RatioMaleOnly, // latios
+	414: RatioMaleOnly, // mothim
+	475: RatioMaleOnly, // gallade
+
+	29: RatioFemaleOnly, 30: RatioFemaleOnly, 31: RatioFemaleOnly, // nidoran♀ line
+	113: RatioFemaleOnly, 242: RatioFemaleOnly, 440: RatioFemaleOnly, // chansey line
+	115: RatioFemaleOnly,                       // kangaskhan
+	124: RatioFemaleOnly, 238: RatioFemaleOnly, // jynx, smoochum
+	241: RatioFemaleOnly, // miltank
+	314: RatioFemaleOnly, // illumise
+	380: RatioFemaleOnly, // latias
+	413: RatioFemaleOnly, // wormadam
+	416: RatioFemaleOnly, // vespiquen
+	478: RatioFemaleOnly, // froslass
+	488: RatioFemaleOnly, // cresselia
+
+	81: RatioGenderless, 82: RatioGenderless, 462: RatioGenderless, // magnemite line
+	100: RatioGenderless, 101: RatioGenderless, // voltorb, electrode
+	120: RatioGenderless, 121: RatioGenderless, // staryu, starmie
+	132: RatioGenderless,                                             // ditto
+	137: RatioGenderless, 233: RatioGenderless, 474: RatioGenderless, // porygon line
+	201: RatioGenderless,                       // unown
+	292: RatioGenderless,                       // shedinja
+	337: RatioGenderless, 338: RatioGenderless, // lunatone, solrock
+	343: RatioGenderless, 344: RatioGenderless, // baltoy, claydol
+	374: RatioGenderless, 375: RatioGenderless, 376: RatioGenderless, // beldum line
+	436: RatioGenderless, 437: RatioGenderless, // bronzor, bronzong
+	479: RatioGenderless, // rotom
+
+	// legendaries and mythicals; heatran is the only gendered one
+	144: RatioGenderless, 145: RatioGenderless, 146: RatioGenderless,
+	150: RatioGenderless, 151: RatioGenderless,
+	243: RatioGenderless, 244: RatioGenderless, 245: RatioGenderless,
+	249: RatioGenderless, 250: RatioGenderless, 251: RatioGenderless,
+	377: RatioGenderless, 378: RatioGenderless, 379: RatioGenderless,
+	382: RatioGenderless, 383: RatioGenderless, 384: RatioGenderless,
+	385: RatioGenderless, 386: RatioGenderless,
+	480: RatioGenderless, 481: RatioGenderless, 482: RatioGenderless,
+	483: RatioGenderless, 484: RatioGenderless, 486: RatioGenderless,
+	487: RatioGenderless, 489: RatioGenderless, 490: RatioGenderless,
+	491: RatioGenderless, 492: RatioGenderless, 493: RatioGenderless,
+}
+
+// Returns the gender ratio of the species with national dex number `species`.
+// ok is false for species outside of generation 4
+func GenderRatioOf(species uint16) (ratio GenderRatio, ok bool) {
+	if species == 0 || species > SPECIES_COUNT {
+		return 0, false
+	}
+
+	if ratio, found := genderRatios[species]; found {
+		return ratio, true
+	}
+
+	return RatioEven, true
+}
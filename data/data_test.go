@@ -0,0 +1,129 @@
+package data
+
+import "testing"
+
+func TestGenderRatioOf(t *testing.T) {
+	cases := map[uint16]GenderRatio{
+		1:   RatioMale7To1,
+		25:  RatioEven,
+		29:  RatioFemaleOnly,
+		32:  RatioMaleOnly,
+		37:  RatioFemale3To1,
+		63:  RatioMale3To1,
+		461: RatioEven,
+		485: RatioEven,
+		487: RatioGenderless,
+	}
+
+	for species, expected := range cases {
+		ratio, ok := GenderRatioOf(species)
+		if !ok || ratio != expected {
+			t.Fatalf("species %d: expected %s, got %s", species, expected, ratio)
+		}
+	}
+
+	for _, species := range []uint16{0, SPECIES_COUNT + 1} {
+		if _, ok := GenderRatioOf(species); ok {
+			t.Fatalf("expected species %d to be unknown", species)
+		}
+	}
+}
+
+func TestBaseStatsOf(t *testing.T) {
+	weavile, ok := BaseStatsOf(461, 0)
+	if !ok || weavile != (BaseStats{70, 120, 65, 45, 85, 125}) {
+		t.Fatalf("unexpected weavile base stats %+v", weavile)
+	}
+
+	if total := weavile.Total(); total != 510 {
+		t.Fatalf("expected a base stat total of 510, got %d", total)
+	}
+
+	origin, ok := BaseStatsOf(487, 1)
+	if !ok || origin.Attack != 120 {
+		t.Fatalf("unexpected giratina origin forme base stats %+v", origin)
+	}
+
+	if _, ok := BaseStatsOf(0, 0); ok {
+		t.Fatal("expected species 0 to be unknown")
+	}
+}
+
+func TestAbilitiesOf(t *testing.T) {
+	weavile, ok := AbilitiesOf(461, 0)
+	if !ok || Ability(weavile[0]).Name != "Pressure" || weavile[1] != 0 {
+		t.Fatalf("unexpected weavile abilities %v", weavile)
+	}
+
+	ability, _ := AbilityFromPID(16, 0, 0x3)
+	if name := Ability(ability).Name; name != "Tangled Feet" {
+		t.Fatalf("expected Tangled Feet for an odd PID, got %s", name)
+	}
+
+	ability, _ = AbilityFromPID(16, 0, 0x2)
+	if name := Ability(ability).Name; name != "Keen Eye" {
+		t.Fatalf("expected Keen Eye for an even PID, got %s", name)
+	}
+
+	origin, _ := AbilitiesOf(487, 1)
+	if name := Ability(origin[0]).Name; name != "Levitate" {
+		t.Fatalf("expected giratina origin forme to have Levitate, got %s", name)
+	}
+}
+
+func TestCanLearn(t *testing.T) {
+	// Night Slash is learned by Weavile, but not by Sneasel
+	if learnable, ok := CanLearn(461, 400); !ok || !learnable {
+		t.Fatal("expected weavile to learn Night Slash")
+	}
+
+	if learnable, ok := CanLearn(215, 400); !ok || learnable {
+		t.Fatal("expected sneasel not to learn Night Slash")
+	}
+
+	// Weavile keeps the egg moves of Sneasel
+	if learnable, _ := CanLearn(461, 420); !learnable {
+		t.Fatal("expected weavile to learn Ice Shard")
+	}
+
+	for _, species := range []uint16{0, 1, SPECIES_COUNT + 1} {
+		if _, ok := CanLearn(species, 33); ok {
+			t.Fatalf("expected no learnset for species %d", species)
+		}
+	}
+}
+
+func TestExperienceForLevel(t *testing.T) {
+	cases := map[GrowthRate][3]uint32{
+		// experience needed for level 2, 50 and 100
+		MediumFast:  {8, 125000, 1000000},
+		Erratic:     {15, 125000, 600000},
+		Fluctuating: {4, 142500, 1640000},
+		MediumSlow:  {9, 117360, 1059860},
+		Fast:        {6, 100000, 800000},
+		Slow:        {10, 156250, 1250000},
+	}
+
+	for rate, expected := range cases {
+		for i, level := range []uint{2, 50, 100} {
+			if exp := rate.ExperienceForLevel(level); exp != expected[i] {
+				t.Fatalf("%s level %d: expected %d, got %d", rate, level, expected[i], exp)
+			}
+		}
+
+		if rate.ExperienceForLevel(1) != 0 {
+			t.Fatalf("%s: expected level 1 to need no experience", rate)
+		}
+	}
+}
+
+func TestLevelFromExperience(t *testing.T) {
+	weavile, _ := GrowthRateOf(461)
+	if level := weavile.LevelFromExperience(191385); level != 58 {
+		t.Fatalf("expected level 58, got %d", level)
+	}
+
+	if level := Fast.LevelFromExperience(0xFFFFFFFF); level != MAX_LEVEL {
+		t.Fatalf("expected level %d, got %d", MAX_LEVEL, level)
+	}
+}
@@ -0,0 +1,59 @@
+package data
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+// every move a species can know in generation 4, one species per line,
+// separated by '|'. This covers level up, TM/HM, egg and tutor moves,
+// including the ones learned by earlier evolutions. Blank lines are species
+// whose learnset is not bundled yet
+//
+//go:embed learnsets.txt
+var learnsetsFile string
+
+var learnsets = parseLearnsets(learnsetsFile)
+
+func moveId(name string) uint16 {
+	for id, n := range moveNames {
+		if id != 0 && n == name {
+			return uint16(id)
+		}
+	}
+	panic(fmt.Sprintf("unknown move %q", name))
+}
+
+// index 0 is left empty so that national dex numbers can be used directly.
+// Only the final newline is trimmed, since trailing species can be blank
+func parseLearnsets(file string) []map[uint16]bool {
+	lines := strings.Split(strings.TrimSuffix(file, "\n"), "\n")
+	res := make([]map[uint16]bool, 1, len(lines)+1)
+
+	for _, line := range lines {
+		if line == "" {
+			res = append(res, nil)
+			continue
+		}
+
+		moves := make(map[uint16]bool)
+		for _, name := range strings.Split(line, "|") {
+			moves[moveId(name)] = true
+		}
+		res = append(res, moves)
+	}
+
+	return res
+}
+
+// Reports whether `species` can know `move` in generation 4.
+// ok is false for species outside of generation 4 and for species whose
+// learnset is not bundled
+func CanLearn(species uint16, move uint16) (learnable bool, ok bool) {
+	if species == 0 || species > SPECIES_COUNT || learnsets[species] == nil {
+		return false, false
+	}
+
+	return learnsets[species][move], true
+}
@@ -0,0 +1,94 @@
+package data
+
+import (
+	_ "embed"
+	"strings"
+)
+
+// English names as they appear in the generation 4 games, one per line.
+// Line N holds the name of ID N; blank lines are unused IDs
+var (
+	//go:embed species.txt
+	speciesFile string
+	//go:embed items.txt
+	itemsFile string
+	//go:embed moves.txt
+	movesFile string
+	//go:embed abilities.txt
+	abilitiesFile string
+)
+
+var (
+	speciesNames = parseNames(speciesFile)
+	itemNames    = parseNames(itemsFile)
+	moveNames    = parseNames(movesFile)
+	abilityNames = parseNames(abilitiesFile)
+)
+
+// index 0 is left empty so that IDs can be used directly
+func parseNames(file string) []string {
+	lines := strings.Split(strings.TrimRight(file, "\n"), "\n")
+	return append([]string{""}, lines...)
+}
+
+func lookup(names []string, id uint) string {
+	if id >= uint(len(names)) {
+		return ""
+	}
+	return names[id]
+}
+
+type SpeciesInfo struct {
+	Id   uint16
+	Name string
+}
+
+type ItemInfo struct {
+	Id   uint16
+	Name string
+}
+
+type MoveInfo struct {
+	Id   uint16
+	Name string
+}
+
+type AbilityInfo struct {
+	Id   uint8
+	Name string
+}
+
+// Name is empty for IDs that don't exist in generation 4
+func Species(id uint16) SpeciesInfo {
+	return SpeciesInfo{id, lookup(speciesNames, uint(id))}
+}
+
+func Item(id uint16) ItemInfo {
+	return ItemInfo{id, lookup(itemNames, uint(id))}
+}
+
+func Move(id uint16) MoveInfo {
+	return MoveInfo{id, lookup(moveNames, uint(id))}
+}
+
+func Ability(id uint8) AbilityInfo {
+	return AbilityInfo{id, lookup(abilityNames, uint(id))}
+}
+
+func (s SpeciesInfo) Known() bool { return s.Name != "" }
+func (i ItemInfo) Known() bool    { return i.Name != "" }
+func (m MoveInfo) Known() bool    { return m.Name != "" }
+func (a AbilityInfo) Known() bool { return a.Name != "" }
+
+func (s SpeciesInfo) String() string { return s.Name }
+func (i ItemInfo) String() string    { return i.Name }
+func (m MoveInfo) String() string    { return m.Name }
+func (a AbilityInfo) String() string { return a.Name }
+
+// highest item, move and ability IDs in generation 4. Items past
+// Platinum's range only exist in HeartGold/SoulSilver
+const (
+	ITEM_COUNT    = 536
+	MOVE_COUNT    = 467
+	ABILITY_COUNT = 123
+)
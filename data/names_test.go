@@ -0,0 +1,55 @@
+package data
+
+import "testing"
+
+func TestTableSizes(t *testing.T) {
+	sizes := map[string][2]int{
+		"species":   {len(speciesNames) - 1, SPECIES_COUNT},
+		"items":     {len(itemNames) - 1, ITEM_COUNT},
+		"moves":     {len(moveNames) - 1, MOVE_COUNT},
+		"abilities": {len(abilityNames) - 1, ABILITY_COUNT},
+		"learnsets": {len(learnsets) - 1, SPECIES_COUNT},
+	}
+
+	for table, size := range sizes {
+		if size[0] != size[1] {
+			t.Fatalf("%s: expected %d entries, got %d", table, size[1], size[0])
+		}
+	}
+}
+
+func TestLookups(t *testing.T) {
+	if name := Species(461).Name; name != "Weavile" {
+		t.Fatalf("expected Weavile, got %q", name)
+	}
+
+	if name := Item(234).Name; name != "Leftovers" {
+		t.Fatalf("expected Leftovers, got %q", name)
+	}
+
+	if name := Move(400).Name; name != "Night Slash" {
+		t.Fatalf("expected Night Slash, got %q", name)
+	}
+
+	if name := Ability(46).Name; name != "Pressure" {
+		t.Fatalf("expected Pressure, got %q", name)
+	}
+}
+
+func TestUnknownIds(t *testing.T) {
+	unknown := []bool{
+		Species(0).Known(),
+		Species(SPECIES_COUNT + 1).Known(),
+		Item(0).Known(),
+		Item(120).Known(), // unused gap between the Griseous and Adamant orbs
+		Item(ITEM_COUNT + 1).Known(),
+		Move(MOVE_COUNT + 1).Known(),
+		Ability(ABILITY_COUNT + 1).Known(),
+	}
+
+	for i, known := range unknown {
+		if known {
+			t.Fatalf("case %d: expected ID to be unknown", i)
+		}
+	}
+}
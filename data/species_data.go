@@ -0,0 +1,191 @@
+package data
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+// abilities a species can have in generation 4, one species per line.
+// Species with two abilities separate them with '|'
+//
+//go:embed species_abilities.txt
+var speciesAbilitiesFile string
+
+// growth rate of every species, one per line
+//
+//go:embed growth_rates.txt
+var growthRatesFile string
+
+var speciesAbilities = parseSpeciesAbilities(speciesAbilitiesFile)
+var growthRates = parseGrowthRates(growthRatesFile)
+
+func abilityId(name string) uint8 {
+	for id, n := range abilityNames {
+		if id != 0 && n == name {
+			return uint8(id)
+		}
+	}
+	panic(fmt.Sprintf("unknown ability %q", name))
+}
+
+// index 0 is left empty so that national dex numbers can be used directly
+func parseSpeciesAbilities(file string) [][2]uint8 {
+	lines := strings.Split(strings.TrimRight(file, "\n"), "\n")
+	res := make([][2]uint8, 1, len(lines)+1)
+
+	for _, line := range lines {
+		var abilities [2]uint8
+		for i, name := range strings.Split(line, "|") {
+			abilities[i] = abilityId(name)
+		}
+		res = append(res, abilities)
+	}
+
+	return res
+}
+
+// alternate forms with a different ability than the species' default form
+var formAbilities = map[uint16]map[uint8][2]uint8{
+	487: {1: {abilityId("Levitate"), 0}},     // giratina origin forme
+	492: {1: {abilityId("Serene Grace"), 0}}, // shaymin sky forme
+}
+
+// Returns the abilities `species` can have in the given form. The second
+// ability is 0 for species with a single ability.
+// ok is false for species outside of generation 4
+func AbilitiesOf(species uint16, form uint8) (abilities [2]uint8, ok bool) {
+	if species == 0 || species > SPECIES_COUNT {
+		return [2]uint8{}, false
+	}
+
+	if abilities, found := formAbilities[species][form]; found {
+		return abilities, true
+	}
+
+	return speciesAbilities[species], true
+}
+
+// Ability a pokemon of the given species has with personality value `pid`.
+// Species with two abilities pick one based on the lowest bit of the PID
+func AbilityFromPID(species uint16, form uint8, pid uint32) (ability uint8, ok bool) {
+	abilities, ok := AbilitiesOf(species, form)
+	if !ok {
+		return 0, false
+	}
+
+	if abilities[1] != 0 && pid&1 == 1 {
+		return abilities[1], true
+	}
+
+	return abilities[0], true
+}
+
+// how much experience a species needs to level up
+type GrowthRate uint8
+
+const (
+	MediumFast GrowthRate = iota
+	Erratic
+	Fluctuating
+	MediumSlow
+	Fast
+	Slow
+)
+
+var growthRateNames = []string{"MediumFast", "Erratic", "Fluctuating", "MediumSlow", "Fast", "Slow"}
+
+func (g GrowthRate) String() string {
+	if int(g) >= len(growthRateNames) {
+		return "Unknown"
+	}
+	return growthRateNames[g]
+}
+
+func parseGrowthRates(file string) []GrowthRate {
+	lines := strings.Split(strings.TrimRight(file, "\n"), "\n")
+	res := make([]GrowthRate, 1, len(lines)+1)
+
+	for i, line := range lines {
+		found := false
+		for rate, name := range growthRateNames {
+			if name == line {
+				res = append(res, GrowthRate(rate))
+				found = true
+			}
+		}
+
+		if !found {
+			panic(fmt.Sprintf("growth_rates.txt line %d: unknown growth rate %q", i+1, line))
+		}
+	}
+
+	return res
+}
+
+// Returns the growth rate of `species`. ok is false for species outside of generation 4
+func GrowthRateOf(species uint16) (rate GrowthRate, ok bool) {
+	if species == 0 || species > SPECIES_COUNT {
+		return 0, false
+	}
+
+	return growthRates[species], true
+}
+
+const MAX_LEVEL = 100
+
+// total experience needed to reach `level`. Levels outside of 1-100 are clamped
+func (g GrowthRate) ExperienceForLevel(level uint) uint32 {
+	if level <= 1 {
+		return 0
+	}
+	if level > MAX_LEVEL {
+		level = MAX_LEVEL
+	}
+
+	n := int64(level)
+	cube := n * n * n
+	var exp int64
+
+	switch g {
+	case Fast:
+		exp = 4 * cube / 5
+	case MediumSlow:
+		exp = 6*cube/5 - 15*n*n + 100*n - 140
+	case Slow:
+		exp = 5 * cube / 4
+	case Erratic:
+		switch {
+		case n < 50:
+			exp = cube * (100 - n) / 50
+		case n < 68:
+			exp = cube * (150 - n) / 100
+		case n < 98:
+			exp = cube * ((1911 - 10*n) / 3) / 500
+		default:
+			exp = cube * (160 - n) / 100
+		}
+	case Fluctuating:
+		switch {
+		case n < 15:
+			exp = cube * ((n+1)/3 + 24) / 50
+		case n < 36:
+			exp = cube * (n + 14) / 50
+		default:
+			exp = cube * (n/2 + 32) / 50
+		}
+	default:
+		exp = cube
+	}
+
+	return uint32(exp)
+}
+
+// level a pokemon with `experience` total experience points is at
+func (g GrowthRate) LevelFromExperience(experience uint32) uint {
+	level := uint(1)
+	for level < MAX_LEVEL && g.ExperienceForLevel(level+1) <= experience {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"errors"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/validator"
+)
+
+var ErrUnknownGame = errors.New("savefile does not match any known game")
+
+type Region uint8
+
+const (
+	RegionUnknown Region = iota
+	RegionJapan
+	RegionNorthAmerica
+	RegionEurope
+	RegionKorea
+)
+
+func (r Region) String() string {
+	switch r {
+	case RegionJapan:
+		return "JP"
+	case RegionNorthAmerica:
+		return "US"
+	case RegionEurope:
+		return "EU"
+	case RegionKorea:
+		return "KR"
+	default:
+		return "Unknown"
+	}
+}
+
+// language IDs stored in the trainer data
+const (
+	LanguageJapanese uint8 = 1
+	LanguageEnglish  uint8 = 2
+	LanguageFrench   uint8 = 3
+	LanguageItalian  uint8 = 4
+	LanguageGerman   uint8 = 5
+	LanguageSpanish  uint8 = 7
+	LanguageKorean   uint8 = 8
+)
+
+func regionFromLanguage(language uint8) Region {
+	switch language {
+	case LanguageJapanese:
+		return RegionJapan
+	case LanguageFrench, LanguageItalian, LanguageGerman, LanguageSpanish:
+		return RegionEurope
+	case LanguageKorean:
+		return RegionKorea
+	default:
+		return RegionUnknown
+	}
+}
+
+type Detection struct {
+	Profile    game_profile.GameProfile
+	Region     Region
+	Language   uint8
+	Confidence float64 // from 0 to 1; 1 means every footer check passed
+}
+
+// Identifies the game a raw savefile belongs to by probing the footers of
+// every known game profile. The returned profile can be passed to Parse.
+// At least one block checksum must match, otherwise ErrUnknownGame is
+// returned.
+//
+// Region detection is not supported: block layouts are shared between
+// regions and the savefile doesn't store one, so Region is only inferred
+// from the language of the game. English releases were sold in North
+// America, Europe and Australia, so they get RegionUnknown
+func DetectGame(savefile []byte) (Detection, error) {
+	if uint(len(savefile)) != game_profile.SAVEFILE_SIZE {
+		return Detection{}, validator.ErrInvalidSize
+	}
+
+	var best validator.Match
+	for _, profile := range game_profile.Profiles {
+		match := validator.MatchProfile(savefile, profile)
+		if match.ValidChecksums > 0 && match.Confidence > best.Confidence {
+			best = match
+		}
+	}
+
+	if best.ValidChecksums == 0 {
+		return Detection{}, ErrUnknownGame
+	}
+
+	languageOffset := best.ChunkOffset + best.Profile.TrainerOffset + LANGUAGE_OFFSET
+	language := savefile[languageOffset]
+
+	return Detection{best.Profile, regionFromLanguage(language), language, best.Confidence}, nil
+}
@@ -0,0 +1,106 @@
+package game_profile
+
+// Savefile layout of a generation 4 game.
+// Small block offsets are relative to the start of the chunk,
+// big block offsets are relative to the start of the big block
+type GameProfile struct {
+	Name string
+
+	// chunk layout; block sizes include the footer
+	SmallBlockSize    uint
+	BigBlockOffset    uint
+	BigBlockSize      uint
+	FooterSize        uint
+	SecondChunkOffset uint
+
+	// small block
+	TrainerOffset        uint
+	AdventureStartOffset uint
+	PartyOffset          uint
+	PokedexOffset        uint
+	BadgeNames           [8]string
+
+	// big block
+	CurrentBoxOffset    uint
+	BoxDataOffset       uint
+	BoxStride           uint // boxes are padded to this size in some games
+	BoxNamesOffset      uint
+	BoxWallpapersOffset uint
+}
+
+const SAVEFILE_SIZE uint = 1 << 19
+
+var sinnohBadges = [8]string{"Coal", "Forest", "Cobble", "Fen", "Relic", "Mine", "Icicle", "Beacon"}
+var johtoBadges = [8]string{"Zephyr", "Hive", "Plain", "Fog", "Storm", "Mineral", "Glacier", "Rising"}
+
+var DiamondPearl = GameProfile{
+	Name:                 "Diamond/Pearl",
+	SmallBlockSize:       0xC100,
+	BigBlockOffset:       0xC100,
+	BigBlockSize:         0x121E0,
+	FooterSize:           0x14,
+	SecondChunkOffset:    0x40000,
+	TrainerOffset:        0x64,
+	AdventureStartOffset: 0x34,
+	PartyOffset:          0x98,
+	PokedexOffset:        0x12DC,
+	BadgeNames:           sinnohBadges,
+	CurrentBoxOffset:     0x0,
+	BoxDataOffset:        0x4,
+	BoxStride:            0xFF0,
+	BoxNamesOffset:       0x11EE4,
+	BoxWallpapersOffset:  0x121B4,
+}
+
+var Platinum = GameProfile{
+	Name:                 "Platinum",
+	SmallBlockSize:       0xCF2C,
+	BigBlockOffset:       0xCF2C,
+	BigBlockSize:         0x121E4,
+	FooterSize:           0x14,
+	SecondChunkOffset:    0x40000,
+	TrainerOffset:        0x68,
+	AdventureStartOffset: 0x34,
+	PartyOffset:          0xA0,
+	PokedexOffset:        0x1328,
+	BadgeNames:           sinnohBadges,
+	CurrentBoxOffset:     0x0,
+	BoxDataOffset:        0x4,
+	BoxStride:            0xFF0,
+	BoxNamesOffset:       0x11EE4,
+	BoxWallpapersOffset:  0x121B4,
+}
+
+// HGSS footers drop the identifier field, and each box is padded to 0x1000 bytes
+var HeartGoldSoulSilver = GameProfile{
+	Name:                 "HeartGold/SoulSilver",
+	SmallBlockSize:       0xF628,
+	BigBlockOffset:       0xF700,
+	BigBlockSize:         0x12310,
+	FooterSize:           0x10,
+	SecondChunkOffset:    0x40000,
+	TrainerOffset:        0x64,
+	AdventureStartOffset: 0x34,
+	PartyOffset:          0x98,
+	PokedexOffset:        0x12B8,
+	BadgeNames:           johtoBadges,
+	CurrentBoxOffset:     0x122FC,
+	BoxDataOffset:        0x0,
+	BoxStride:            0x1000,
+	BoxNamesOffset:       0x12008,
+	BoxWallpapersOffset:  0x122D8,
+}
+
+var Profiles = []GameProfile{DiamondPearl, Platinum, HeartGoldSoulSilver}
+
+func (p GameProfile) SmallBlockFooterOffset() uint {
+	return p.SmallBlockSize - p.FooterSize
+}
+
+func (p GameProfile) BigBlockFooterOffset() uint {
+	return p.BigBlockOffset + p.BigBlockSize - p.FooterSize
+}
+
+func (p GameProfile) String() string {
+	return p.Name
+}
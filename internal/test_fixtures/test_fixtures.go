@@ -0,0 +1,35 @@
+// Fixtures shared by the tests of several packages
+package test_fixtures
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+// path of the mock party pokemon, resolved from this file so it works
+// from the test of any package
+func mockPokemonPath() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "rom_reader", "mock_pokemon_data")
+}
+
+// decodes the level 100 Weavile stored in rom_reader/mock_pokemon_data
+func Weavile(t *testing.T) rom_reader.Pokemon {
+	t.Helper()
+
+	savefile, err := os.ReadFile(mockPokemonPath())
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon, err := rom_reader.GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	return pokemon
+}
@@ -0,0 +1,337 @@
+package legality
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/data"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/stat_calculator"
+)
+
+type Severity uint8
+
+const (
+	// the pokemon is possible but unusual, e.g. edited stats that the
+	// game would fix on the next level up
+	Warning Severity = iota
+	// the game can't produce the pokemon
+	Error
+	// the check could not be performed, so the report is not a complete verdict
+	NotChecked
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Error:
+		return "error"
+	case NotChecked:
+		return "not checked"
+	default:
+		return "warning"
+	}
+}
+
+// names the check a finding comes from
+type Check string
+
+const (
+	CheckSpecies  Check = "species"
+	CheckEVs      Check = "evs"
+	CheckIVs      Check = "ivs"
+	CheckLevel    Check = "level"
+	CheckStats    Check = "stats"
+	CheckAbility  Check = "ability"
+	CheckMoves    Check = "moves"
+	CheckLearnset Check = "learnset"
+	CheckBall     Check = "ball"
+	CheckMet      Check = "met"
+	CheckNature   Check = "nature"
+	CheckGender   Check = "gender"
+)
+
+type Finding struct {
+	Check    Check
+	Severity Severity
+	Message  string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s", f.Severity, f.Check, f.Message)
+}
+
+const MAX_EV = 255
+const MAX_EV_TOTAL = 510
+const MAX_IV = 31
+const MAX_PP_UPS = 3
+
+// ball IDs as stored in block D
+const (
+	POKE_BALL    = 4
+	CHERISH_BALL = 16
+)
+
+type Report struct {
+	Pokemon  rom_reader.Pokemon
+	Findings []Finding
+}
+
+// true if no check found the pokemon impossible to obtain.
+// Checks that were not performed don't count; see Complete
+func (r Report) Legal() bool {
+	for _, f := range r.Findings {
+		if f.Severity == Error {
+			return false
+		}
+	}
+	return true
+}
+
+// true if every check was performed, so Legal is a full legality verdict
+func (r Report) Complete() bool {
+	for _, f := range r.Findings {
+		if f.Severity == NotChecked {
+			return false
+		}
+	}
+	return true
+}
+
+// legality report format specifier
+func (r Report) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(
+		&sb, "%s (#%d) legal=%t complete=%t\n",
+		data.Species(r.Pokemon.PokedexId), r.Pokemon.PokedexId, r.Legal(), r.Complete(),
+	)
+	for _, f := range r.Findings {
+		fmt.Fprintf(&sb, "\t%s\n", f)
+	}
+
+	return sb.String()
+}
+
+type checker struct {
+	findings []Finding
+}
+
+func (c *checker) add(check Check, severity Severity, format string, args ...any) {
+	c.findings = append(c.findings, Finding{check, severity, fmt.Sprintf(format, args...)})
+}
+
+// Runs every legality check on `p`. Empty slots produce an empty report.
+// Only some learnsets are bundled; species without one get a NotChecked
+// learnset finding, and their report is not Complete
+func CheckPokemon(p rom_reader.Pokemon) Report {
+	c := &checker{}
+	if p.IsEmpty() {
+		return Report{p, nil}
+	}
+
+	c.checkEVs(p)
+	c.checkIVs(p)
+	c.checkNature(p)
+	c.checkMoves(p)
+	c.checkBall(p)
+
+	if data.Species(p.PokedexId).Known() {
+		c.checkLevel(p)
+		c.checkStats(p)
+		c.checkAbility(p)
+		c.checkGender(p)
+	} else {
+		c.add(CheckSpecies, Error, "unknown species %d", p.PokedexId)
+	}
+
+	c.checkLearnset(p)
+
+	return Report{p, c.findings}
+}
+
+func (c *checker) checkEVs(p rom_reader.Pokemon) {
+	for _, stat := range rom_reader.AllStats {
+		if ev := p.EVs.Get(stat); ev > MAX_EV {
+			c.add(CheckEVs, Error, "%s EV %d exceeds %d", stat, ev, MAX_EV)
+		}
+	}
+
+	if total := p.EVs.Total(); total > MAX_EV_TOTAL {
+		c.add(CheckEVs, Error, "EV total %d exceeds %d", total, MAX_EV_TOTAL)
+	}
+}
+
+func (c *checker) checkIVs(p rom_reader.Pokemon) {
+	for _, stat := range rom_reader.AllStats {
+		if iv := p.IVs.Get(stat); iv > MAX_IV {
+			c.add(CheckIVs, Error, "%s IV %d exceeds %d", stat, iv, MAX_IV)
+		}
+	}
+}
+
+// The nature is stored nowhere but in the PID, so a nature that doesn't
+// match it was edited in. Party pokemon also carry the nature their battle
+// stats were computed with, which shows when the PID was edited instead
+func (c *checker) checkNature(p rom_reader.Pokemon) {
+	expected := rom_reader.NatureFromPID(p.Personality)
+	if p.Nature != expected {
+		c.add(CheckNature, Error, "nature %s does not match the PID, which gives %s", p.Nature, expected)
+		return
+	}
+
+	base, ok := data.BaseStatsOf(p.PokedexId, p.Form)
+	if p.Level == 0 || !ok {
+		return
+	}
+
+	// neutral natures give the same stats, so more than one can match
+	var matching []rom_reader.Nature
+	for _, nature := range rom_reader.Natures {
+		if stat_calculator.CalculateStats(base, p.IVs, p.EVs, p.Level, nature) == p.Stats {
+			matching = append(matching, nature)
+		}
+	}
+
+	// stats that no nature explains are reported by the stats check
+	if len(matching) == 0 || slices.Contains(matching, expected) {
+		return
+	}
+
+	c.add(CheckNature, Warning, "battle stats were computed with a %s nature, but the PID gives %s", matching[0], expected)
+}
+
+func (c *checker) checkMoves(p rom_reader.Pokemon) {
+	if p.Moves[0] == 0 {
+		c.add(CheckMoves, Error, "pokemon has no moves")
+	}
+
+	seen := map[uint16]bool{}
+	for i, move := range p.Moves {
+		if p.MovePPUps[i] > MAX_PP_UPS {
+			c.add(CheckMoves, Error, "move %d has %d PP ups, the maximum is %d", i+1, p.MovePPUps[i], MAX_PP_UPS)
+		}
+
+		if move == 0 {
+			continue
+		}
+
+		if !data.Move(move).Known() {
+			c.add(CheckMoves, Error, "move %d has unknown ID %d", i+1, move)
+			continue
+		}
+
+		if i > 0 && p.Moves[i-1] == 0 {
+			c.add(CheckMoves, Error, "%s follows an empty move slot", data.Move(move))
+		}
+
+		if seen[move] {
+			c.add(CheckMoves, Error, "%s is known more than once", data.Move(move))
+		}
+		seen[move] = true
+	}
+}
+
+func (c *checker) checkLearnset(p rom_reader.Pokemon) {
+	species := data.Species(p.PokedexId)
+	if _, ok := data.CanLearn(p.PokedexId, 0); !ok {
+		c.add(CheckLearnset, NotChecked, "the learnset of %s is not bundled, so its moves were not checked", species)
+		return
+	}
+
+	for _, move := range p.Moves {
+		if move == 0 || !data.Move(move).Known() {
+			continue
+		}
+
+		if learnable, _ := data.CanLearn(p.PokedexId, move); !learnable {
+			c.add(CheckLearnset, Error, "%s can't learn %s", species, data.Move(move))
+		}
+	}
+}
+
+func (c *checker) checkBall(p rom_reader.Pokemon) {
+	if p.PokeBall == 0 || p.PokeBall > CHERISH_BALL {
+		c.add(CheckBall, Error, "unknown ball %d", p.PokeBall)
+		return
+	}
+
+	if p.PokeBall == CHERISH_BALL && !p.FatefulEncounter {
+		c.add(CheckBall, Error, "Cherish Ball is only used for event pokemon")
+	}
+
+	if p.IsEgg {
+		return
+	}
+
+	// generation 4 eggs always hatch in a Poke Ball at level 0
+	if p.EggLocationId() != 0 {
+		if p.PokeBall != POKE_BALL {
+			c.add(CheckBall, Error, "hatched pokemon must be in a Poke Ball, not %s", data.Item(uint16(p.PokeBall)))
+		}
+
+		if p.MetLevel != 0 {
+			c.add(CheckMet, Error, "hatched pokemon must have a met level of 0, not %d", p.MetLevel)
+		}
+	}
+
+	if p.MetLocationId() == 0 {
+		c.add(CheckMet, Warning, "met location is unset")
+	}
+}
+
+func (c *checker) checkLevel(p rom_reader.Pokemon) {
+	rate, _ := data.GrowthRateOf(p.PokedexId)
+	maxExp := rate.ExperienceForLevel(data.MAX_LEVEL)
+	if p.Experience > maxExp {
+		c.add(CheckLevel, Error, "experience %d exceeds the level 100 cap of %d", p.Experience, maxExp)
+	}
+
+	level := rate.LevelFromExperience(p.Experience)
+	if uint(p.MetLevel) > level {
+		c.add(CheckLevel, Error, "met at level %d but currently level %d", p.MetLevel, level)
+	}
+
+	// box pokemon have no stored level to compare with
+	if p.Level == 0 {
+		return
+	}
+
+	if p.Level > data.MAX_LEVEL {
+		c.add(CheckLevel, Error, "level %d exceeds %d", p.Level, data.MAX_LEVEL)
+	} else if p.Level != level {
+		c.add(CheckLevel, Error, "level %d does not match its experience, which gives level %d", p.Level, level)
+	}
+}
+
+func (c *checker) checkStats(p rom_reader.Pokemon) {
+	discrepancies, err := stat_calculator.CheckBattleStats(p)
+	if err != nil {
+		return
+	}
+
+	for _, d := range discrepancies {
+		c.add(CheckStats, Warning, "%s", d)
+	}
+}
+
+func (c *checker) checkAbility(p rom_reader.Pokemon) {
+	abilities, _ := data.AbilitiesOf(p.PokedexId, p.Form)
+	ability := uint8(p.AbilityId)
+
+	if p.AbilityId > 0xFF || (ability != abilities[0] && (abilities[1] == 0 || ability != abilities[1])) {
+		c.add(CheckAbility, Error, "%s can't have ability %d", data.Species(p.PokedexId), p.AbilityId)
+		return
+	}
+
+	expected, _ := data.AbilityFromPID(p.PokedexId, p.Form, p.Personality)
+	if ability != expected {
+		c.add(CheckAbility, Error, "ability %s does not match the PID, which gives %s", data.Ability(ability), data.Ability(expected))
+	}
+}
+
+func (c *checker) checkGender(p rom_reader.Pokemon) {
+	if expected, _ := p.PIDGender(); p.GenderMismatch() {
+		c.add(CheckGender, Error, "gender %s does not match the PID, which gives %s", p.Gender, expected)
+	}
+}
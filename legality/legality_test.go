@@ -0,0 +1,222 @@
+package legality
+
+import (
+	"testing"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/internal/test_fixtures"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+// checks that `report` holds exactly one finding per entry of `expected`,
+// besides the checks that were not performed
+func expectFindings(t *testing.T, report Report, expected ...Check) {
+	t.Helper()
+
+	var findings []Finding
+	for _, f := range report.Findings {
+		if f.Severity != NotChecked {
+			findings = append(findings, f)
+		}
+	}
+
+	if len(findings) != len(expected) {
+		t.Fatalf("expected findings %v, got:\n%s", expected, report)
+	}
+
+	for i, check := range expected {
+		if findings[i].Check != check {
+			t.Fatalf("expected findings %v, got:\n%s", expected, report)
+		}
+	}
+}
+
+func TestCheckPokemonLegal(t *testing.T) {
+	report := CheckPokemon(test_fixtures.Weavile(t))
+	if !report.Legal() || !report.Complete() {
+		t.Fatalf("expected a complete report of a legal pokemon, got:\n%s", report)
+	}
+	expectFindings(t, report)
+}
+
+func TestCheckPokemonLearnset(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	// Thunderbolt
+	weavile.Moves[3] = 85
+
+	report := CheckPokemon(weavile)
+	if report.Legal() {
+		t.Fatalf("expected an illegal pokemon, got:\n%s", report)
+	}
+	expectFindings(t, report, CheckLearnset)
+}
+
+func TestCheckPokemonLearnsetNotChecked(t *testing.T) {
+	// the learnset of pidgey is not bundled
+	pidgey := rom_reader.Pokemon{
+		Personality: 0x3,
+		PokedexId:   16,
+		Nature:      rom_reader.NatureFromPID(0x3),
+		AbilityId:   77,
+		Moves:       [4]uint16{33},
+		PokeBall:    POKE_BALL,
+		MetLocation: 16,
+		MetLevel:    1,
+		Gender:      rom_reader.Female,
+	}
+
+	report := CheckPokemon(pidgey)
+	if report.Complete() {
+		t.Fatalf("a report without learnset checks should not be complete:\n%s", report)
+	}
+
+	found := false
+	for _, f := range report.Findings {
+		if f.Check == CheckLearnset && f.Severity == NotChecked {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected a learnset finding that was not checked, got:\n%s", report)
+	}
+}
+
+func TestCheckPokemonNature(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.Nature = rom_reader.Natures[(weavile.Nature.Index+1)%25]
+
+	report := CheckPokemon(weavile)
+	if report.Legal() {
+		t.Fatalf("expected an illegal pokemon, got:\n%s", report)
+	}
+	if report.Findings[0].Check != CheckNature {
+		t.Fatalf("expected a nature finding first, got:\n%s", report)
+	}
+}
+
+func TestCheckPokemonNatureStats(t *testing.T) {
+	// a PID edited without recalculating the stats, which were computed
+	// with the previous nature
+	weavile := test_fixtures.Weavile(t)
+	// the next PID has the next nature, and the same gender
+	weavile.Personality++
+	weavile.Nature = rom_reader.NatureFromPID(weavile.Personality)
+
+	report := CheckPokemon(weavile)
+	if !report.Legal() {
+		t.Fatalf("expected stale stats to only be warnings, got:\n%s", report)
+	}
+	if report.Findings[0].Check != CheckNature || report.Findings[0].Severity != Warning {
+		t.Fatalf("expected a nature warning first, got:\n%s", report)
+	}
+}
+
+func TestCheckPokemonEmpty(t *testing.T) {
+	report := CheckPokemon(rom_reader.Pokemon{})
+	if !report.Legal() || !report.Complete() || len(report.Findings) != 0 {
+		t.Fatalf("expected an empty report, got:\n%s", report)
+	}
+}
+
+func TestCheckPokemonEVs(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.EVs = rom_reader.Stats{Hp: 252, Attack: 252, Speed: 252}
+	weavile.Stats = rom_reader.Stats{Hp: 200, Attack: 181, Defense: 93, SpAttack: 63, SpDefense: 106, Speed: 215}
+
+	report := CheckPokemon(weavile)
+	if report.Legal() {
+		t.Fatalf("expected an illegal pokemon, got:\n%s", report)
+	}
+	expectFindings(t, report, CheckEVs)
+}
+
+func TestCheckPokemonLevel(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.Level = 100
+
+	report := CheckPokemon(weavile)
+	if report.Legal() {
+		t.Fatalf("expected an illegal pokemon, got:\n%s", report)
+	}
+
+	// the stats are also off, since they were computed for level 58
+	if report.Findings[0].Check != CheckLevel || report.Findings[1].Check != CheckStats {
+		t.Fatalf("expected level and stat findings, got:\n%s", report)
+	}
+}
+
+func TestCheckPokemonStats(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.Stats.Speed = 999
+
+	report := CheckPokemon(weavile)
+	if !report.Legal() {
+		t.Fatalf("expected unrecalculated stats to only be a warning, got:\n%s", report)
+	}
+	expectFindings(t, report, CheckStats)
+}
+
+func TestCheckPokemonPID(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.Gender = rom_reader.Female
+	weavile.AbilityId = 65
+
+	report := CheckPokemon(weavile)
+	expectFindings(t, report, CheckAbility, CheckGender)
+}
+
+func TestCheckPokemonAbilitySlot(t *testing.T) {
+	// pidgey with Tangled Feet needs an odd PID
+	pidgey := rom_reader.Pokemon{
+		Personality: 0x2,
+		PokedexId:   16,
+		Nature:      rom_reader.NatureFromPID(0x2),
+		AbilityId:   77,
+		Moves:       [4]uint16{33},
+		PokeBall:    POKE_BALL,
+		MetLocation: 16,
+		MetLevel:    1,
+		Gender:      rom_reader.Female,
+	}
+
+	expectFindings(t, CheckPokemon(pidgey), CheckAbility)
+
+	pidgey.Personality = 0x3
+	pidgey.Nature = rom_reader.NatureFromPID(0x3)
+	expectFindings(t, CheckPokemon(pidgey))
+}
+
+func TestCheckPokemonMoves(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.Moves = [4]uint16{400, 0, 400, 999}
+	weavile.MovePPUps[0] = 4
+
+	report := CheckPokemon(weavile)
+	expectFindings(t, report, CheckMoves, CheckMoves, CheckMoves, CheckMoves)
+}
+
+func TestCheckPokemonHatched(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.EggLocation = 2000
+	weavile.PokeBall = 1
+
+	report := CheckPokemon(weavile)
+	expectFindings(t, report, CheckBall, CheckMet)
+}
+
+func TestCheckPokemonCherishBall(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.PokeBall = CHERISH_BALL
+
+	expectFindings(t, CheckPokemon(weavile), CheckBall)
+
+	weavile.FatefulEncounter = true
+	expectFindings(t, CheckPokemon(weavile))
+}
+
+func TestCheckPokemonUnknownSpecies(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.PokedexId = 600
+
+	expectFindings(t, CheckPokemon(weavile), CheckSpecies)
+}
@@ -3,25 +3,132 @@ package parser
 import (
 	"fmt"
 
+	"github.com/dingdongg/pkmn-platinum-rom-parser/char_encoder"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
 	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_writer"
 	"github.com/dingdongg/pkmn-platinum-rom-parser/validator"
 )
 
-const PERSONALITY_OFFSET = 0xA0
+const PARTY_SIZE = rom_reader.PARTY_SIZE
+const BOX_COUNT = 18
+const BOX_SIZE = 30
+const BOX_NAME_SIZE = 40
 
-func Parse(savefile []byte) []rom_reader.Pokemon {
-	valid := validator.Validate(savefile)
+type Box struct {
+	Name      string
+	Wallpaper uint8
+	Pokemon   []rom_reader.Pokemon // unused slots are reported as empty pokemon
+}
+
+type SaveFile struct {
+	Blocks     validator.SaveBlocks // chunks the small and big block were read from
+	Trainer    Trainer
+	Party      []rom_reader.Pokemon
+	CurrentBox uint
+	Boxes      []Box
+	SlotErrors []SlotError // slots that failed to decode, in party then box order
+}
+
+// a party or box slot that could not be decoded. The slot itself is
+// reported as an empty pokemon so the rest of the save stays usable
+type SlotError struct {
+	InParty bool
+	Box     uint // only set for box slots
+	Slot    uint
+	Err     error
+}
+
+func (e SlotError) Error() string {
+	if e.InParty {
+		return fmt.Sprintf("party slot %d: %s", e.Slot, e.Err)
+	}
+	return fmt.Sprintf("box %d slot %d: %s", e.Box, e.Slot, e.Err)
+}
 
-	if valid {
-		fmt.Println("SAVEFILE IS VALID")
+func (e SlotError) Unwrap() error {
+	return e.Err
+}
+
+// Parses the trainer and party from the most recent valid small block, and the
+// PC boxes from the most recent valid big block, using the layout of `profile`.
+// Nothing is printed. Save-level errors from the validator package are returned
+// as-is so callers can inspect them with errors.Is/errors.As. Pokemon that fail
+// to decode don't fail the parse; they are listed in SlotErrors instead
+func Parse(savefile []byte, profile game_profile.GameProfile) (*SaveFile, error) {
+	blocks, err := validator.MostRecentBlocks(savefile, profile)
+	if err != nil {
+		return nil, err
 	}
 
-	// TODO: only read from/edit the most recent savefiel
+	partyOffset := blocks.SmallChunk + profile.PartyOffset
 	var res []rom_reader.Pokemon
+	var slotErrors []SlotError
+
+	for i := uint(0); i < PARTY_SIZE; i++ {
+		pokemon, err := rom_reader.GetPokemon(savefile[partyOffset:], i)
+		if err != nil {
+			slotErrors = append(slotErrors, SlotError{InParty: true, Slot: i, Err: err})
+		}
+
+		res = append(res, pokemon)
+	}
+
+	storage := savefile[blocks.BigChunk+profile.BigBlockOffset:]
+	boxes, boxErrors := parseBoxes(storage, profile)
+	slotErrors = append(slotErrors, boxErrors...)
+
+	currentBox := uint(storage[profile.CurrentBoxOffset])
+
+	trainer := getTrainer(savefile[blocks.SmallChunk:], profile)
+
+	return &SaveFile{blocks, trainer, res, currentBox, boxes, slotErrors}, nil
+}
+
+// `storage` must be a slice starting at the big block
+func parseBoxes(storage []byte, profile game_profile.GameProfile) ([]Box, []SlotError) {
+	var boxes []Box
+	var slotErrors []SlotError
+
+	for i := uint(0); i < BOX_COUNT; i++ {
+		boxOffset := profile.BoxDataOffset + i*profile.BoxStride
+		var pokemon []rom_reader.Pokemon
+
+		for j := uint(0); j < BOX_SIZE; j++ {
+			p, err := rom_reader.GetBoxPokemon(storage[boxOffset:], j)
+			if err != nil {
+				slotErrors = append(slotErrors, SlotError{Box: i, Slot: j, Err: err})
+			}
+
+			pokemon = append(pokemon, p)
+		}
+
+		nameOffset := profile.BoxNamesOffset + i*BOX_NAME_SIZE
+		name := char_encoder.DecodeBytes(storage[nameOffset : nameOffset+BOX_NAME_SIZE])
+		wallpaper := storage[profile.BoxWallpapersOffset+i]
+
+		boxes = append(boxes, Box{name, wallpaper, pokemon})
+	}
+
+	return boxes, slotErrors
+}
+
+// Writes `pokemon` into the given party slot of the most recent valid small
+// block, then recomputes the checksums so the game accepts the file
+func WritePokemon(savefile []byte, profile game_profile.GameProfile, partyIndex uint, pokemon rom_reader.Pokemon) error {
+	if partyIndex >= PARTY_SIZE {
+		return rom_reader.ErrOutOfRange
+	}
+
+	blocks, err := validator.MostRecentBlocks(savefile, profile)
+	if err != nil {
+		return err
+	}
 
-	for i := uint(0); i < 6; i++ {
-		res = append(res, rom_reader.GetPokemon(savefile[PERSONALITY_OFFSET:], i))
+	partyOffset := blocks.SmallChunk + profile.PartyOffset
+	if err := rom_writer.SetPokemon(savefile[partyOffset:], partyIndex, pokemon); err != nil {
+		return fmt.Errorf("party slot %d: %w", partyIndex, err)
 	}
 
-	return res
+	return validator.UpdateChecksums(savefile, profile, blocks)
 }
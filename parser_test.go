@@ -0,0 +1,256 @@
+package parser
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/validator"
+)
+
+// builds a savefile with valid checksums and the mock pokemon
+// in the first party slot of the first chunk
+func mockSavefile(t *testing.T, profile game_profile.GameProfile) []byte {
+	pokemon, err := os.ReadFile("./rom_reader/mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	savefile := make([]byte, game_profile.SAVEFILE_SIZE)
+	copy(savefile[profile.PartyOffset:], pokemon)
+
+	// footers store the size of their block
+	sizeOffset := profile.FooterSize - 0xC
+	smallBlockSize := savefile[profile.SmallBlockFooterOffset()+sizeOffset:]
+	binary.LittleEndian.PutUint32(smallBlockSize, uint32(profile.SmallBlockSize))
+	bigBlockSize := savefile[profile.BigBlockFooterOffset()+sizeOffset:]
+	binary.LittleEndian.PutUint32(bigBlockSize, uint32(profile.BigBlockSize))
+
+	if err := validator.UpdateChecksums(savefile, profile, validator.SaveBlocks{}); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	return savefile
+}
+
+func TestParse(t *testing.T) {
+	for _, profile := range game_profile.Profiles {
+		savefile := mockSavefile(t, profile)
+
+		res, err := Parse(savefile, profile)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", profile, err)
+		}
+
+		if res.Party[0].Name != "WEAVILE" {
+			t.Fatalf("%s: expected WEAVILE, got '%s'", profile, res.Party[0].Name)
+		}
+
+		if !res.Party[1].IsEmpty() || !res.Boxes[0].Pokemon[0].IsEmpty() {
+			t.Fatalf("%s: expected empty slots", profile)
+		}
+
+		if len(res.Boxes) != BOX_COUNT || len(res.Boxes[BOX_COUNT-1].Pokemon) != BOX_SIZE {
+			t.Fatalf("%s: unexpected box layout", profile)
+		}
+	}
+}
+
+// sets the save number stored in the footer that starts at `footerAddr`
+func setSaveNumber(savefile []byte, profile game_profile.GameProfile, footerAddr uint, saveNumber uint32) {
+	addr := footerAddr + profile.FooterSize - 0x10
+	binary.LittleEndian.PutUint32(savefile[addr:], saveNumber)
+}
+
+func TestParseBlocksFromDifferentChunks(t *testing.T) {
+	profile := game_profile.Platinum
+	savefile := mockSavefile(t, profile)
+	second := profile.SecondChunkOffset
+	weavile := savefile[profile.PartyOffset : profile.PartyOffset+rom_reader.PARTY_POKEMON_SIZE]
+
+	// the second chunk has the newest small block, holding an empty party
+	copy(savefile[second:], savefile[:profile.SmallBlockSize])
+	copy(savefile[second+profile.PartyOffset:], make([]byte, rom_reader.PARTY_POKEMON_SIZE))
+	setSaveNumber(savefile, profile, second+profile.SmallBlockFooterOffset(), 4)
+
+	// its big block predates the last box change, which was saved in the first chunk
+	bigBlock := profile.BigBlockOffset
+	copy(savefile[second+bigBlock:], savefile[bigBlock:bigBlock+profile.BigBlockSize])
+	setSaveNumber(savefile, profile, second+profile.BigBlockFooterOffset(), 2)
+
+	copy(savefile[bigBlock+profile.BoxDataOffset:], weavile[:rom_reader.BOX_POKEMON_SIZE])
+	savefile[bigBlock+profile.CurrentBoxOffset] = 7
+	setSaveNumber(savefile, profile, profile.SmallBlockFooterOffset(), 3)
+	setSaveNumber(savefile, profile, profile.BigBlockFooterOffset(), 3)
+
+	for _, chunkOffset := range []uint{0, second} {
+		blocks := validator.SaveBlocks{SmallChunk: chunkOffset, BigChunk: chunkOffset}
+		if err := validator.UpdateChecksums(savefile, profile, blocks); err != nil {
+			t.Fatal("Unexpected error ", err)
+		}
+	}
+
+	res, err := Parse(savefile, profile)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if res.Blocks != (validator.SaveBlocks{SmallChunk: second, BigChunk: 0}) {
+		t.Fatalf("expected the small block from 0x%x and big block from 0x0, got %+v", second, res.Blocks)
+	}
+
+	if !res.Party[0].IsEmpty() {
+		t.Fatalf("expected the party of the newest small block, got %s", res.Party[0].Name)
+	}
+
+	if res.Boxes[0].Pokemon[0].Name != "WEAVILE" || res.CurrentBox != 7 {
+		t.Fatalf("expected the boxes of the newest big block, got '%s' in box %d", res.Boxes[0].Pokemon[0].Name, res.CurrentBox)
+	}
+}
+
+func TestParseSlotErrors(t *testing.T) {
+	profile := game_profile.Platinum
+	savefile := mockSavefile(t, profile)
+	weavile := savefile[profile.PartyOffset : profile.PartyOffset+rom_reader.PARTY_POKEMON_SIZE]
+
+	// a corrupt copy in party slot 2, and in the first slot of box 3
+	partySlot := profile.PartyOffset + 2*rom_reader.PARTY_POKEMON_SIZE
+	copy(savefile[partySlot:], weavile)
+	savefile[partySlot+0x10] ^= 0xFF
+
+	boxSlot := profile.BigBlockOffset + profile.BoxDataOffset + 3*profile.BoxStride
+	copy(savefile[boxSlot:], weavile[:rom_reader.BOX_POKEMON_SIZE])
+	savefile[boxSlot+0x10] ^= 0xFF
+
+	if err := validator.UpdateChecksums(savefile, profile, validator.SaveBlocks{}); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	res, err := Parse(savefile, profile)
+	if err != nil {
+		t.Fatal("a corrupt pokemon should not fail the parse: ", err)
+	}
+
+	if res.Party[0].Name != "WEAVILE" {
+		t.Fatalf("expected WEAVILE, got '%s'", res.Party[0].Name)
+	}
+
+	expected := []SlotError{{InParty: true, Slot: 2}, {Box: 3, Slot: 0}}
+	if len(res.SlotErrors) != len(expected) {
+		t.Fatalf("expected %d slot errors, got %v", len(expected), res.SlotErrors)
+	}
+
+	for i, e := range expected {
+		actual := res.SlotErrors[i]
+		if actual.InParty != e.InParty || actual.Box != e.Box || actual.Slot != e.Slot {
+			t.Fatalf("expected %+v, got %+v", e, actual)
+		}
+
+		var checksumErr *rom_reader.ChecksumError
+		if !errors.As(actual, &checksumErr) {
+			t.Fatalf("expected a ChecksumError, got %v", actual.Err)
+		}
+	}
+
+	if !res.Party[2].IsEmpty() || !res.Boxes[3].Pokemon[0].IsEmpty() {
+		t.Fatal("corrupt slots should be reported as empty")
+	}
+}
+
+func TestWritePokemon(t *testing.T) {
+	profile := game_profile.Platinum
+	savefile := mockSavefile(t, profile)
+
+	res, err := Parse(savefile, profile)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	weavile := res.Party[0]
+	weavile.HeldItemId = 217
+	if err := WritePokemon(savefile, profile, 0, weavile); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	res, err = Parse(savefile, profile)
+	if err != nil {
+		t.Fatal("savefile invalid after writing: ", err)
+	}
+
+	if res.Party[0].HeldItemId != 217 {
+		t.Fatalf("expected held item 217, got %d", res.Party[0].HeldItemId)
+	}
+}
+
+func TestDetectGame(t *testing.T) {
+	for _, profile := range game_profile.Profiles {
+		savefile := mockSavefile(t, profile)
+
+		detection, err := DetectGame(savefile)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", profile, err)
+		}
+
+		if detection.Profile.Name != profile.Name || detection.Confidence != 1 {
+			t.Fatalf("%s: got %s with confidence %f", profile, detection.Profile, detection.Confidence)
+		}
+	}
+}
+
+func TestDetectGameRegion(t *testing.T) {
+	profile := game_profile.Platinum
+	savefile := mockSavefile(t, profile)
+	savefile[profile.TrainerOffset+LANGUAGE_OFFSET] = LanguageGerman
+
+	if err := validator.UpdateChecksums(savefile, profile, validator.SaveBlocks{}); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	detection, err := DetectGame(savefile)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if detection.Region != RegionEurope || detection.Language != LanguageGerman {
+		t.Fatalf("expected a german EU savefile, got %s (language %d)", detection.Region, detection.Language)
+	}
+
+	// english releases were sold in several regions
+	savefile[profile.TrainerOffset+LANGUAGE_OFFSET] = LanguageEnglish
+	if err := validator.UpdateChecksums(savefile, profile, validator.SaveBlocks{}); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	detection, err = DetectGame(savefile)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if detection.Region != RegionUnknown || detection.Language != LanguageEnglish {
+		t.Fatalf("expected an english savefile of unknown region, got %s (language %d)", detection.Region, detection.Language)
+	}
+}
+
+func TestDetectGameUnknown(t *testing.T) {
+	_, err := DetectGame(make([]byte, game_profile.SAVEFILE_SIZE))
+	if !errors.Is(err, ErrUnknownGame) {
+		t.Fatalf("expected ErrUnknownGame, got %v", err)
+	}
+}
+
+func TestDetectGameWithoutChecksums(t *testing.T) {
+	// block sizes alone match a layout, but no checksum does
+	for _, profile := range game_profile.Profiles {
+		savefile := make([]byte, game_profile.SAVEFILE_SIZE)
+		sizeOffset := profile.FooterSize - 0xC
+		binary.LittleEndian.PutUint32(savefile[profile.SmallBlockFooterOffset()+sizeOffset:], uint32(profile.SmallBlockSize))
+		binary.LittleEndian.PutUint32(savefile[profile.BigBlockFooterOffset()+sizeOffset:], uint32(profile.BigBlockSize))
+
+		if _, err := DetectGame(savefile); !errors.Is(err, ErrUnknownGame) {
+			t.Fatalf("%s: expected ErrUnknownGame, got %v", profile, err)
+		}
+	}
+}
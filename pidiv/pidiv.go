@@ -0,0 +1,271 @@
+package pidiv
+
+import (
+	"github.com/dingdongg/pkmn-platinum-rom-parser/data"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/prng"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+// the PID/IV correlation a pokemon was generated with
+type Method uint8
+
+const (
+	// stationary encounters and gifts: PID low, PID high, IVs
+	Method1 Method = iota
+	// Diamond/Pearl/Platinum wild encounters: a nature call, then PIDs
+	// are rerolled until they match that nature
+	MethodJ
+	// HeartGold/SoulSilver wild encounters, same as MethodJ with a different nature call
+	MethodK
+	// shiny pokemon from Poke Radar chains
+	ChainShiny
+	// wondercard pokemon whose Method 1 PID was rerolled with the ARNG to avoid being shiny
+	WondercardARNG
+	// PID derived from the trainer IDs, nature and gender
+	Pokewalker
+	// egg PIDs come from a separate Mersenne Twister, so only the origin is known
+	Egg
+)
+
+func (m Method) String() string {
+	switch m {
+	case Method1:
+		return "Method 1"
+	case MethodJ:
+		return "Method J"
+	case MethodK:
+		return "Method K"
+	case ChainShiny:
+		return "Chained Shiny"
+	case WondercardARNG:
+		return "Wondercard (ARNG)"
+	case Pokewalker:
+		return "Pokewalker"
+	case Egg:
+		return "Egg"
+	default:
+		return "Unknown"
+	}
+}
+
+type Match struct {
+	Method Method
+	// PRNG state right before the method's first call
+	Seed uint32
+	// the method's first call was made this many frames after InitialSeed.
+	// Both are 0 if no plausible initial seed was found within MAX_FRAMES
+	Frame       uint32
+	InitialSeed uint32
+	// MethodJ and MethodK only: the nature came from a Synchronize lead
+	Synchronize bool
+}
+
+// how far back to look for a plausible initial seed
+const MAX_FRAMES = 100000
+
+// delays above this are too long to hit on purpose
+const MAX_DELAY = 10000
+
+// how many rerolled PIDs to walk back through when looking for a nature call
+const MAX_REROLLS = 1000
+
+// inverse of the alternate LCG used for wondercard rerolls
+const (
+	arngRevMult = 0x9638806D
+	arngRevAdd  = 0x69C77F93
+)
+
+func next(seed uint32) uint32 { return seed*prng.LCG_MULT + prng.LCG_ADD }
+func prev(seed uint32) uint32 { return seed*prng.LCG_REV_MULT + prng.LCG_REV_ADD }
+func high(seed uint32) uint16 { return uint16(seed >> 16) }
+
+func arngPrev(pid uint32) uint32 { return pid*arngRevMult + arngRevAdd }
+
+// initial seeds are ((month * day + minute + second) << 24) | (hour << 16) | (delay + year - 2000)
+func isInitialSeed(seed uint32) bool {
+	return (seed>>16)&0xFF < 24 && seed&0xFFFF <= MAX_DELAY
+}
+
+// walks back from `seed` to the nearest plausible initial seed
+func findFrame(seed uint32) (initialSeed uint32, frame uint32, ok bool) {
+	s := seed
+	for frame := uint32(1); frame <= MAX_FRAMES; frame++ {
+		if isInitialSeed(s) {
+			return s, frame, true
+		}
+		s = prev(s)
+	}
+	return 0, 0, false
+}
+
+func newMatch(method Method, seed uint32, synchronize bool) Match {
+	initialSeed, frame, _ := findFrame(seed)
+	return Match{method, seed, frame, initialSeed, synchronize}
+}
+
+// IVs are split into two 15-bit halves, HP/Atk/Def then Spe/SpA/SpD
+func splitIVs(ivs rom_reader.Stats) (uint16, uint16) {
+	packed := rom_reader.PackIVs(ivs)
+	return uint16(packed & 0x7FFF), uint16(packed >> 15 & 0x7FFF)
+}
+
+// Returns every seed that generates `pid` followed by the two IV halves
+// on consecutive calls, as methods 1, J and K all do
+func pidChainSeeds(pid uint32, iv1, iv2 uint16) []uint32 {
+	var res []uint32
+	pidLow, pidHigh := uint16(pid), uint16(pid>>16)
+
+	for low := uint32(0); low <= 0xFFFF; low++ {
+		s := uint32(pidLow)<<16 | low
+		if high(next(s)) != pidHigh {
+			continue
+		}
+
+		ivSeed := next(next(s))
+		if high(ivSeed)&0x7FFF != iv1 || high(next(ivSeed))&0x7FFF != iv2 {
+			continue
+		}
+
+		res = append(res, prev(s))
+	}
+
+	return res
+}
+
+// rules of the nature call and Synchronize check preceding the PID rolls
+type natureCall struct {
+	method      Method
+	nature      func(rand uint16) uint32
+	synchronize func(rand uint16) bool
+}
+
+var methodJ = natureCall{
+	MethodJ,
+	func(rand uint16) uint32 { return uint32(rand) / 0xA3E },
+	func(rand uint16) bool { return rand>>15 == 0 },
+}
+
+var methodK = natureCall{
+	MethodK,
+	func(rand uint16) uint32 { return uint32(rand) % 25 },
+	func(rand uint16) bool { return rand%2 == 0 },
+}
+
+// Walks back from `seed`, the state before the PID was rolled, through
+// rerolled PIDs and returns every call that could have picked the nature.
+// The walk stops at a rerolled PID with the same nature, which the game
+// would have kept
+func (n natureCall) find(seed uint32, pid uint32) []Match {
+	var res []Match
+	nature := pid % 25
+
+	s := seed
+	for i := 0; i < MAX_REROLLS; i++ {
+		if n.nature(high(s)) == nature {
+			res = append(res, newMatch(n.method, prev(s), false))
+		}
+
+		if n.synchronize(high(s)) {
+			res = append(res, newMatch(n.method, prev(s), true))
+		}
+
+		// otherwise the previous two calls rolled a PID with another nature
+		rerolled := uint32(high(s))<<16 | uint32(high(prev(s)))
+		if rerolled%25 == nature {
+			break
+		}
+		s = prev(prev(s))
+	}
+
+	return res
+}
+
+// Chained shiny PIDs are built from the low 3 bits of two calls and one bit
+// of 13 more calls, followed by the IVs. The rest of the PID high half
+// comes from the trainer IDs, which makes the pokemon shiny
+func chainShinySeeds(pid uint32, iv1, iv2 uint16) []uint32 {
+	var res []uint32
+	pidLow, pidHigh := uint16(pid), uint16(pid>>16)
+
+	for bit15 := uint32(0); bit15 <= 1; bit15++ {
+		for low := uint32(0); low <= 0xFFFF; low++ {
+			ivSeed := (uint32(iv1)|bit15<<15)<<16 | low
+			if high(next(ivSeed))&0x7FFF != iv2 {
+				continue
+			}
+
+			// calls 15 down to 3 hold bits 15 down to 3 of the PID low half
+			s := prev(ivSeed)
+			ok := true
+			for bit := 15; bit >= 3 && ok; bit-- {
+				ok = high(s)&1 == (pidLow>>bit)&1
+				s = prev(s)
+			}
+
+			if ok && high(s)&7 == pidHigh&7 && high(prev(s))&7 == pidLow&7 {
+				res = append(res, prev(prev(s)))
+			}
+		}
+	}
+
+	return res
+}
+
+// Searches backwards with the Gen IV LCG for the methods that tie a PID to
+// its IVs. Every match found is returned, most specific method last
+func Find(pid uint32, ivs rom_reader.Stats) []Match {
+	var res []Match
+	iv1, iv2 := splitIVs(ivs)
+
+	for _, seed := range pidChainSeeds(pid, iv1, iv2) {
+		res = append(res, newMatch(Method1, seed, false))
+		res = append(res, methodJ.find(seed, pid)...)
+		res = append(res, methodK.find(seed, pid)...)
+	}
+
+	for _, seed := range pidChainSeeds(arngPrev(pid), iv1, iv2) {
+		res = append(res, newMatch(WondercardARNG, seed, false))
+	}
+
+	for _, seed := range chainShinySeeds(pid, iv1, iv2) {
+		res = append(res, newMatch(ChainShiny, seed, false))
+	}
+
+	return res
+}
+
+// Pokewalker PIDs start from the trainer IDs and nature, and are moved
+// by multiples of 25 until the low byte gives the requested gender
+func isPokewalkerPID(pid uint32, tid, sid uint16) bool {
+	base := ((uint32(tid^sid) >> 8) ^ 0xFF) << 24
+	base += pid%25 - base%25
+
+	diff := int64(pid) - int64(base)
+	return diff%25 == 0 && diff > -0x100 && diff < 0x100
+}
+
+// Same as Find, but also checks the methods that depend on the rest of the
+// pokemon: chained shinies must be shiny, Pokewalker PIDs depend on the
+// trainer IDs and eggs are reported as such
+func Analyze(p rom_reader.Pokemon) []Match {
+	var res []Match
+
+	for _, m := range Find(p.Personality, p.IVs) {
+		if m.Method == ChainShiny && !p.IsShiny() {
+			continue
+		}
+		res = append(res, m)
+	}
+
+	if ratio, ok := data.GenderRatioOf(p.PokedexId); ok && isPokewalkerPID(p.Personality, p.OTId, p.OTSecretId) {
+		if rom_reader.GenderFromPID(p.Personality, ratio) == p.Gender {
+			res = append(res, Match{Method: Pokewalker})
+		}
+	}
+
+	if p.IsEgg || p.EggLocationId() != 0 {
+		res = append(res, Match{Method: Egg})
+	}
+
+	return res
+}
@@ -0,0 +1,173 @@
+package pidiv
+
+import (
+	"testing"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/prng"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+func ivsFromCalls(iv1, iv2 uint16) rom_reader.Stats {
+	iv := func(half uint16, i uint) uint { return uint(half>>(5*i)) & 0x1F }
+	return rom_reader.Stats{
+		Hp: iv(iv1, 0), Attack: iv(iv1, 1), Defense: iv(iv1, 2),
+		Speed: iv(iv2, 0), SpAttack: iv(iv2, 1), SpDefense: iv(iv2, 2),
+	}
+}
+
+// rolls a PID and IVs from `seed` the way the game does for each method
+func generate(method Method, seed uint32, tid, sid uint16) (uint32, rom_reader.Stats) {
+	s := seed
+	call := func() uint16 {
+		s = next(s)
+		return high(s)
+	}
+
+	var pid uint32
+	switch method {
+	case Method1:
+		pid = uint32(call()) | uint32(call())<<16
+	case MethodJ, MethodK:
+		nature := methodJ.nature(call())
+		if method == MethodK {
+			nature = uint32(high(s)) % 25
+		}
+		for pid = uint32(call()) | uint32(call())<<16; pid%25 != nature; {
+			pid = uint32(call()) | uint32(call())<<16
+		}
+	case ChainShiny:
+		low, upper := call()&7, call()&7
+		for i := 3; i < 16; i++ {
+			low |= (call() & 1) << i
+		}
+		upper |= (low ^ tid ^ sid) & 0xFFF8
+		pid = uint32(upper)<<16 | uint32(low)
+	}
+
+	iv1, iv2 := call()&0x7FFF, call()&0x7FFF
+	return pid, ivsFromCalls(iv1, iv2)
+}
+
+func findMatch(matches []Match, method Method, seed uint32) (Match, bool) {
+	for _, m := range matches {
+		if m.Method == method && m.Seed == seed {
+			return m, true
+		}
+	}
+	return Match{}, false
+}
+
+func TestInverseConstants(t *testing.T) {
+	for _, seed := range []uint32{0, 1, 0x12345678, 0xFFFFFFFF} {
+		if prev(next(seed)) != seed {
+			t.Fatalf("prev is not the inverse of next for 0x%x", seed)
+		}
+
+		if arngPrev(seed*prng.ARNG_MULT+prng.ARNG_ADD) != seed {
+			t.Fatalf("arngPrev is not the inverse of the ARNG for 0x%x", seed)
+		}
+	}
+}
+
+func TestFindMethod1(t *testing.T) {
+	seed := uint32(0x0A0F0320)
+	pid, ivs := generate(Method1, seed, 0, 0)
+
+	m, ok := findMatch(Find(pid, ivs), Method1, seed)
+	if !ok {
+		t.Fatalf("expected a Method 1 match with seed 0x%x", seed)
+	}
+
+	// the seed itself looks like an initial seed: hour 15, delay 800
+	if m.InitialSeed != seed || m.Frame != 1 {
+		t.Fatalf("expected frame 1 from 0x%x, got frame %d from 0x%x", seed, m.Frame, m.InitialSeed)
+	}
+}
+
+func TestFindMethodJK(t *testing.T) {
+	for _, method := range []Method{MethodJ, MethodK} {
+		for seed := uint32(0x1234); seed < 0x1234+50; seed++ {
+			pid, ivs := generate(method, seed*0x10001, 0, 0)
+
+			m, ok := findMatch(Find(pid, ivs), method, seed*0x10001)
+			if !ok || m.Synchronize {
+				t.Fatalf("expected a %s match with seed 0x%x", method, seed*0x10001)
+			}
+		}
+	}
+}
+
+func TestFindWondercardARNG(t *testing.T) {
+	seed := uint32(0xDEADBEEF)
+	pid, ivs := generate(Method1, seed, 0, 0)
+	rerolled := pid*prng.ARNG_MULT + prng.ARNG_ADD
+
+	if _, ok := findMatch(Find(rerolled, ivs), WondercardARNG, seed); !ok {
+		t.Fatalf("expected a wondercard match with seed 0x%x", seed)
+	}
+}
+
+func TestFindChainShiny(t *testing.T) {
+	seed := uint32(0xCAFEBABE)
+	tid, sid := uint16(26241), uint16(11961)
+	pid, ivs := generate(ChainShiny, seed, tid, sid)
+
+	if !rom_reader.IsShiny(pid, tid, sid) {
+		t.Fatalf("expected chained PID 0x%x to be shiny", pid)
+	}
+
+	matches := Analyze(rom_reader.Pokemon{
+		Personality: pid, PokedexId: 461, IVs: ivs, OTId: tid, OTSecretId: sid,
+		Gender: rom_reader.GenderFromPID(pid, 127),
+	})
+	if _, ok := findMatch(matches, ChainShiny, seed); !ok {
+		t.Fatalf("expected a chained shiny match with seed 0x%x, got %+v", seed, matches)
+	}
+}
+
+func TestFindWeavile(t *testing.T) {
+	// the mock weavile was caught in the wild in Platinum
+	ivs := rom_reader.Stats{Hp: 25, Attack: 1, Defense: 23, SpAttack: 25, SpDefense: 5, Speed: 17}
+	matches := Find(0x94DFB7DB, ivs)
+
+	if _, ok := findMatch(matches, Method1, 0x29B7FED0); !ok {
+		t.Fatalf("expected the PID and IVs to be rolled from 0x29b7fed0, got %+v", matches)
+	}
+
+	m, ok := findMatch(matches, MethodJ, 0xA7097D55)
+	if !ok || m.Synchronize {
+		t.Fatalf("expected a Method J match with seed 0xa7097d55, got %+v", matches)
+	}
+}
+
+func TestFindNoMatch(t *testing.T) {
+	ivs := rom_reader.Stats{Hp: 31, Attack: 31, Defense: 31, SpAttack: 31, SpDefense: 31, Speed: 31}
+	if matches := Find(0x12345678, ivs); len(matches) != 0 {
+		t.Fatalf("expected no matches, got %+v", matches)
+	}
+}
+
+func TestAnalyzePokewalker(t *testing.T) {
+	tid, sid := uint16(12345), uint16(54321)
+	base := ((uint32(tid^sid) >> 8) ^ 0xFF) << 24
+	pid := base + 13 - base%25 // jolly
+
+	p := rom_reader.Pokemon{Personality: pid, PokedexId: 25, OTId: tid, OTSecretId: sid}
+	p.Gender = rom_reader.GenderFromPID(pid, 127)
+
+	found := false
+	for _, m := range Analyze(p) {
+		found = found || m.Method == Pokewalker
+	}
+
+	if !found {
+		t.Fatalf("expected PID 0x%x to be a Pokewalker PID", pid)
+	}
+}
+
+func TestAnalyzeEgg(t *testing.T) {
+	matches := Analyze(rom_reader.Pokemon{PokedexId: 25, EggLocation: 2000, Personality: 0x12345678})
+	if len(matches) == 0 || matches[len(matches)-1].Method != Egg {
+		t.Fatalf("expected an egg match, got %+v", matches)
+	}
+}
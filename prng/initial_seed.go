@@ -0,0 +1,101 @@
+package prng
+
+import (
+	"slices"
+	"time"
+)
+
+// years the DS clock can be set to
+const (
+	MIN_YEAR = 2000
+	MAX_YEAR = 2099
+)
+
+// the date and time the game was started at, and how many frames it waited
+// before seeding its PRNGs
+type SeedTime struct {
+	Time  time.Time
+	Delay uint32
+}
+
+// Computes the initial seed the game derives from `t` and `delay`, laid out
+// as AB CD EFGH:
+// AB is (month * day + minute + second) mod 256, CD is the hour and EFGH is
+// the delay plus the years since 2000
+func InitialSeed(t time.Time, delay uint32) uint32 {
+	ab := uint32(int(t.Month())*t.Day()+t.Minute()+t.Second()) & 0xFF
+	cd := uint32(t.Hour())
+	efgh := delay + uint32(t.Year()-MIN_YEAR)
+
+	return ab<<24 + cd<<16 + efgh
+}
+
+func (s SeedTime) Seed() uint32 {
+	return InitialSeed(s.Time, s.Delay)
+}
+
+// Lists every date, time and delay from `fromYear` to `toYear` that gives
+// `seed`, in chronological order. Delays above `maxDelay` are skipped.
+// EFGH can exceed 0xFFFF, in which case it carries into the hour and AB, so
+// every delay matching the low 16 bits of the seed is tried and the hour
+// and AB are taken from what remains
+func SearchInitialSeed(seed uint32, fromYear, toYear int, maxDelay uint32) []SeedTime {
+	var res []SeedTime
+
+	// an hour and the AB it must be seen with, for a given delay
+	type candidate struct {
+		ab    int
+		hour  int
+		delay uint32
+	}
+
+	fromYear = max(fromYear, MIN_YEAR)
+	toYear = min(toYear, MAX_YEAR)
+
+	for year := fromYear; year <= toYear; year++ {
+		var candidates []candidate
+		yearOffset := uint32(year - MIN_YEAR)
+
+		for delay := uint64((seed - yearOffset) & 0xFFFF); delay <= uint64(maxDelay); delay += 0x10000 {
+			rest := seed - yearOffset - uint32(delay)
+			hour := int(rest >> 16 & 0xFF)
+			if hour > 23 {
+				continue
+			}
+			candidates = append(candidates, candidate{int(rest >> 24), hour, uint32(delay)})
+		}
+
+		if len(candidates) == 0 {
+			continue
+		}
+
+		for month := time.January; month <= time.December; month++ {
+			// day 0 of the next month is the last day of this one
+			days := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+			for day := 1; day <= days; day++ {
+				for minute := 0; minute < 60; minute++ {
+					for second := 0; second < 60; second++ {
+						ab := (int(month)*day + minute + second) & 0xFF
+
+						for _, c := range candidates {
+							if c.ab != ab {
+								continue
+							}
+
+							t := time.Date(year, month, day, c.hour, minute, second, 0, time.UTC)
+							res = append(res, SeedTime{t, c.delay})
+						}
+					}
+				}
+			}
+		}
+	}
+
+	// candidates of a day can have different hours
+	slices.SortStableFunc(res, func(a, b SeedTime) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return res
+}
@@ -0,0 +1,88 @@
+package prng
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInitialSeed(t *testing.T) {
+	tests := []struct {
+		time     time.Time
+		delay    uint32
+		expected uint32
+	}{
+		// 3 * 15 + 20 + 30 = 95
+		{time.Date(2009, time.March, 15, 10, 20, 30, 0, time.UTC), 600, 0x5F0A0261},
+		// 12 * 31 + 59 + 59 = 490, which wraps around to 234
+		{time.Date(2000, time.December, 31, 23, 59, 59, 0, time.UTC), 0, 0xEA170000},
+	}
+
+	for _, test := range tests {
+		if actual := InitialSeed(test.time, test.delay); actual != test.expected {
+			t.Fatalf("%s delay %d: expected 0x%08X, got 0x%08X", test.time, test.delay, test.expected, actual)
+		}
+	}
+}
+
+func TestSearchInitialSeed(t *testing.T) {
+	target := time.Date(2009, time.March, 15, 10, 20, 30, 0, time.UTC)
+	seed := InitialSeed(target, 600)
+
+	results := SearchInitialSeed(seed, 2008, 2010, 10000)
+	found := false
+	for _, r := range results {
+		if r.Seed() != seed {
+			t.Fatalf("%s delay %d gives 0x%08X, not 0x%08X", r.Time, r.Delay, r.Seed(), seed)
+		}
+
+		if r.Time.Equal(target) && r.Delay == 600 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected %s delay 600 among %d results", target, len(results))
+	}
+
+	for i := 1; i < len(results); i++ {
+		if !results[i-1].Time.Before(results[i].Time) {
+			t.Fatalf("results are not in chronological order at %d", i)
+		}
+	}
+}
+
+func TestSearchInitialSeedCarry(t *testing.T) {
+	// 65530 + 9 = 0x10003 carries into the hour, giving 0x5F0B0003
+	target := time.Date(2009, time.March, 15, 10, 20, 30, 0, time.UTC)
+	seed := InitialSeed(target, 65530)
+	if seed != 0x5F0B0003 {
+		t.Fatalf("expected 0x5F0B0003, got 0x%08X", seed)
+	}
+
+	found := false
+	for _, r := range SearchInitialSeed(seed, 2009, 2009, 70000) {
+		if r.Seed() != seed {
+			t.Fatalf("%s delay %d gives 0x%08X, not 0x%08X", r.Time, r.Delay, r.Seed(), seed)
+		}
+
+		if r.Time.Equal(target) && r.Delay == 65530 {
+			found = true
+		}
+	}
+
+	if !found {
+		t.Fatalf("expected %s delay 65530", target)
+	}
+}
+
+func TestSearchInitialSeedBounds(t *testing.T) {
+	// hour 24 can't be set on the DS clock
+	if results := SearchInitialSeed(0x5F180261, 2000, 2099, 10000); len(results) != 0 {
+		t.Fatalf("expected no results for an invalid hour, got %d", len(results))
+	}
+
+	// delay 600 in 2009 is above the maximum delay
+	if results := SearchInitialSeed(0x5F0A0261, 2009, 2009, 500); len(results) != 0 {
+		t.Fatalf("expected no results above the maximum delay, got %d", len(results))
+	}
+}
@@ -0,0 +1,110 @@
+package prng
+
+// MT19937 parameters
+const (
+	MT_STATE_SIZE = 624
+	mtShift       = 397
+	mtMatrix      = 0x9908B0DF
+	mtUpperMask   = 0x80000000
+	mtLowerMask   = 0x7FFFFFFF
+)
+
+// Mersenne Twister (MT19937), used by generation 4 to roll egg PIDs.
+// The game seeds it with the same initial seed as the LCG
+type MT struct {
+	state [MT_STATE_SIZE]uint32
+	index int
+}
+
+func InitMT(seed uint32) MT {
+	var m MT
+	m.state[0] = seed
+	for i := 1; i < MT_STATE_SIZE; i++ {
+		prev := m.state[i-1]
+		m.state[i] = 0x6C078965*(prev^(prev>>30)) + uint32(i)
+	}
+	m.index = MT_STATE_SIZE
+	return m
+}
+
+// regenerates the whole state once every MT_STATE_SIZE calls
+func (m *MT) twist() {
+	for i := 0; i < MT_STATE_SIZE; i++ {
+		y := m.state[i]&mtUpperMask | m.state[(i+1)%MT_STATE_SIZE]&mtLowerMask
+		next := m.state[(i+mtShift)%MT_STATE_SIZE] ^ y>>1
+		if y&1 == 1 {
+			next ^= mtMatrix
+		}
+		m.state[i] = next
+	}
+	m.index = 0
+}
+
+func (m *MT) Next() uint32 {
+	if m.index >= MT_STATE_SIZE {
+		m.twist()
+	}
+
+	y := m.state[m.index]
+	m.index++
+
+	// tempering
+	y ^= y >> 11
+	y ^= y << 7 & 0x9D2C5680
+	y ^= y << 15 & 0xEFC60000
+	y ^= y >> 18
+	return y
+}
+
+// Skips the next `n` outputs. Whole blocks of MT_STATE_SIZE outputs are
+// skipped with a single twist each, without tempering the skipped values
+func (m *MT) Advance(n uint64) {
+	for n > 0 {
+		if m.index >= MT_STATE_SIZE {
+			m.twist()
+		}
+
+		step := uint64(MT_STATE_SIZE - m.index)
+		if n < step {
+			step = n
+		}
+		m.index += int(step)
+		n -= step
+	}
+}
+
+// chances at a shiny PID an egg gets with the Masuda method, including the first roll
+const MASUDA_ROLLS = 5
+
+type EggPID struct {
+	// the PID is the Frame-th output of the Mersenne Twister, starting at 1
+	Frame uint32
+	PID   uint32
+	Shiny bool
+}
+
+func isShiny(pid uint32, tid, sid uint16) bool {
+	return tid^sid^uint16(pid>>16)^uint16(pid) < 8
+}
+
+// Predicts the PIDs of eggs generated on frames 1 through `frames` after
+// the Mersenne Twister was seeded with `initialSeed`. Masuda method eggs
+// reroll non-shiny PIDs with the ARNG, up to MASUDA_ROLLS - 1 times
+func EggPIDs(initialSeed uint32, frames uint32, masuda bool, tid, sid uint16) []EggPID {
+	res := make([]EggPID, 0, frames)
+	mt := InitMT(initialSeed)
+
+	for frame := uint32(1); frame <= frames; frame++ {
+		pid := mt.Next()
+		shiny := isShiny(pid, tid, sid)
+
+		for roll := 1; masuda && !shiny && roll < MASUDA_ROLLS; roll++ {
+			pid = pid*ARNG_MULT + ARNG_ADD
+			shiny = isShiny(pid, tid, sid)
+		}
+
+		res = append(res, EggPID{frame, pid, shiny})
+	}
+
+	return res
+}
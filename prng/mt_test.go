@@ -0,0 +1,80 @@
+package prng
+
+import (
+	"testing"
+)
+
+func TestMTReferenceSequence(t *testing.T) {
+	// first outputs of the reference implementation with its default seed
+	mt := InitMT(5489)
+	expected := []uint32{3499211612, 581869302, 3890346734, 3586334585, 545404204}
+
+	for i, e := range expected {
+		if actual := mt.Next(); actual != e {
+			t.Fatalf("output %d: expected %d, got %d", i+1, e, actual)
+		}
+	}
+}
+
+func TestMTAdvance(t *testing.T) {
+	// the 10000th output of the default seed is fixed by the C++ standard
+	mt := InitMT(5489)
+	mt.Advance(9999)
+
+	if actual := mt.Next(); actual != 4123659995 {
+		t.Fatalf("expected 4123659995, got %d", actual)
+	}
+
+	for _, n := range []uint64{0, 1, 623, 624, 625, 1500} {
+		stepped := InitMT(0x12345678)
+		for i := uint64(0); i < n; i++ {
+			stepped.Next()
+		}
+
+		jumped := InitMT(0x12345678)
+		jumped.Advance(n)
+		if a, b := stepped.Next(), jumped.Next(); a != b {
+			t.Fatalf("Advance(%d): expected %d, got %d", n, a, b)
+		}
+	}
+}
+
+func TestEggPIDs(t *testing.T) {
+	seed := uint32(0x0A0F0320)
+	eggs := EggPIDs(seed, 3, false, 0, 0)
+	mt := InitMT(seed)
+
+	if len(eggs) != 3 {
+		t.Fatalf("expected 3 eggs, got %d", len(eggs))
+	}
+
+	for i, egg := range eggs {
+		pid := mt.Next()
+		if egg.Frame != uint32(i+1) || egg.PID != pid {
+			t.Fatalf("egg %d: expected frame %d pid 0x%x, got frame %d pid 0x%x", i, i+1, pid, egg.Frame, egg.PID)
+		}
+	}
+}
+
+func TestEggPIDsMasuda(t *testing.T) {
+	seed := uint32(0x0A0F0320)
+	mt := InitMT(seed)
+	pid := mt.Next()
+
+	// trainer IDs that make the second reroll shiny
+	rerolled := pid
+	for i := 0; i < 2; i++ {
+		rerolled = rerolled*ARNG_MULT + ARNG_ADD
+	}
+	tid, sid := uint16(rerolled>>16), uint16(rerolled)
+
+	egg := EggPIDs(seed, 1, true, tid, sid)[0]
+	if !egg.Shiny || egg.PID != rerolled {
+		t.Fatalf("expected shiny pid 0x%x, got 0x%x (shiny %t)", rerolled, egg.PID, egg.Shiny)
+	}
+
+	egg = EggPIDs(seed, 1, false, tid, sid)[0]
+	if egg.PID != pid || egg.Shiny != isShiny(pid, tid, sid) {
+		t.Fatalf("expected pid 0x%x without Masuda, got 0x%x", pid, egg.PID)
+	}
+}
@@ -1,36 +1,88 @@
 package prng
 
+// Gen IV LCG constants, and the ones undoing a step
+const (
+	LCG_MULT     uint32 = 0x41C64E6D
+	LCG_ADD      uint32 = 0x6073
+	LCG_REV_MULT uint32 = 0xEEB9EB65
+	LCG_REV_ADD  uint32 = 0x0A3561A1
+)
+
+// alternate LCG, used to reroll wondercard and Masuda method PIDs
+const (
+	ARNG_MULT uint32 = 0x6C078965
+	ARNG_ADD  uint32 = 0x1
+)
+
+// 32-bit linear congruential generator shared by every Gen IV PRNG:
+// state = state * LCG_MULT + LCG_ADD. Only the upper 16 bits of the
+// state are returned; the full state is kept for future calls
+type LCG struct {
+	State uint32
+}
+
+func (l *LCG) Next() uint16 {
+	l.State = l.State*LCG_MULT + LCG_ADD
+	return uint16(l.State >> 16)
+}
+
+// undoes the last call to Next, returning the value that call returned
+func (l *LCG) Prev() uint16 {
+	res := uint16(l.State >> 16)
+	l.State = l.State*LCG_REV_MULT + LCG_REV_ADD
+	return res
+}
+
+// Moves the state `n` calls forward in O(log n) steps. Stepping n times
+// is the same as a single LCG with multiplier mult^n and increment
+// add * (mult^(n-1) + ... + mult + 1), built here by repeated squaring
+func (l *LCG) Advance(n uint64) {
+	mult, add := LCG_MULT, LCG_ADD
+
+	for ; n > 0; n >>= 1 {
+		if n&1 == 1 {
+			l.State = l.State*mult + add
+		}
+		add *= mult + 1
+		mult *= mult
+	}
+}
+
+// Number of calls to Next needed to get from state `from` to state `to`.
+// The LCG has a period of 2^32, so every state is reachable. Bit i of the
+// state only depends on the lowest i+1 bits of the distance, so the
+// distance is found one bit at a time
+func Distance(from, to uint32) uint32 {
+	mult, add := LCG_MULT, LCG_ADD
+	res := uint32(0)
+
+	for bit := uint32(1); bit != 0; bit <<= 1 {
+		if (from^to)&bit != 0 {
+			from = from*mult + add
+			res |= bit
+		}
+		add *= mult + 1
+		mult *= mult
+	}
+
+	return res
+}
+
 type PRNG struct {
 	Checksum    uint16
 	Personality uint32
-	PrevResult  uint
+	LCG
 }
 
 type BattleStatPRNG struct {
-	PrevResult  uint
+	LCG
 	Personality uint32
 }
 
 func InitBattleStatPRNG(personality uint32) BattleStatPRNG {
-	return BattleStatPRNG{uint(personality), personality}
-}
-
-func (bsprng *BattleStatPRNG) Next() uint16 {
-	result := 0x041C64E6D*bsprng.PrevResult + 0x06073
-	bsprng.PrevResult = result
-	result >>= 16
-	// return the upper 16 bits only for external use; internally, all bits should be held for future calls
-	return uint16(result & 0xFFFF)
+	return BattleStatPRNG{LCG{personality}, personality}
 }
 
 func Init(checksum uint16, personality uint32) PRNG {
-	return PRNG{checksum, personality, uint(checksum)}
-}
-
-func (prng *PRNG) Next() uint16 {
-	result := 0x041C64E6D*prng.PrevResult + 0x06073
-	prng.PrevResult = result
-	result >>= 16
-	// return the upper 16 bits only for external use; internally, all bits should be held for future calls
-	return uint16(result & 0xFFFF)
+	return PRNG{checksum, personality, LCG{uint32(checksum)}}
 }
@@ -17,8 +17,8 @@ func TestInit(t *testing.T) {
 		t.Fatalf("Invalid personality; expected 0x%x, got 0x%x", personality, actual.Personality)
 	}
 
-	if actual.PrevResult != uint(checksum) {
-		t.Fatalf("Invalid prevResult; expected 0x%x, got 0x%x", uint(checksum), actual.PrevResult)
+	if actual.State != uint32(checksum) {
+		t.Fatalf("Invalid state; expected 0x%x, got 0x%x", uint32(checksum), actual.State)
 	}
 }
 
@@ -40,12 +40,12 @@ func TestNextInternals(t *testing.T) {
 	prng := Init(checksum, personality)
 
 	numCalls := 3
-	expectedValues := []uint{0x6073, 0x18C7E97E7B6A, 0xF47F2B6C52713895}
+	expectedValues := []uint32{0x6073, 0xE97E7B6A, 0x52713895}
 
 	for i := 0; i < numCalls; i++ {
 		prng.Next()
-		if prng.PrevResult != expectedValues[i] {
-			t.Fatalf("expected 0x%x, got 0x%x", expectedValues[i], prng.PrevResult)
+		if prng.State != expectedValues[i] {
+			t.Fatalf("expected 0x%x, got 0x%x", expectedValues[i], prng.State)
 		}
 	}
 }
@@ -58,8 +58,8 @@ func TestBattleStatInit(t *testing.T) {
 		t.Fatalf("Invalid personality; expected 0x%x, got 0x%x", personality, actual.Personality)
 	}
 
-	if actual.PrevResult != uint(personality) {
-		t.Fatalf("Invalid prevResult; expected 0x%x, got 0x%x", uint(personality), actual.PrevResult)
+	if actual.State != personality {
+		t.Fatalf("Invalid state; expected 0x%x, got 0x%x", personality, actual.State)
 	}
 }
 
@@ -79,12 +79,56 @@ func TestBSNextInternals(t *testing.T) {
 	prng := InitBattleStatPRNG(personality)
 
 	numCalls := 3
-	expectedValues := []uint{0x6073, 0x18C7E97E7B6A, 0xF47F2B6C52713895}
+	expectedValues := []uint32{0x6073, 0xE97E7B6A, 0x52713895}
 
 	for i := 0; i < numCalls; i++ {
 		prng.Next()
-		if prng.PrevResult != expectedValues[i] {
-			t.Fatalf("expected 0x%x, got 0x%x", expectedValues[i], prng.PrevResult)
+		if prng.State != expectedValues[i] {
+			t.Fatalf("expected 0x%x, got 0x%x", expectedValues[i], prng.State)
+		}
+	}
+}
+
+func TestPrev(t *testing.T) {
+	lcg := LCG{0x12345678}
+
+	values := []uint16{lcg.Next(), lcg.Next(), lcg.Next()}
+	for i := len(values) - 1; i >= 0; i-- {
+		if ret := lcg.Prev(); ret != values[i] {
+			t.Fatalf("call %d: expected 0x%x, got 0x%x", i, values[i], ret)
+		}
+	}
+
+	if lcg.State != 0x12345678 {
+		t.Fatalf("expected state 0x12345678, got 0x%x", lcg.State)
+	}
+}
+
+func TestAdvance(t *testing.T) {
+	tests := []uint64{0, 1, 2, 3, 100, 12345, 1 << 32, 1<<32 + 7}
+
+	for _, n := range tests {
+		stepped := LCG{0xDEADBEEF}
+		for i := uint64(0); i < n%(1<<32); i++ {
+			stepped.Next()
+		}
+
+		jumped := LCG{0xDEADBEEF}
+		jumped.Advance(n)
+		if jumped.State != stepped.State {
+			t.Fatalf("Advance(%d): expected 0x%x, got 0x%x", n, stepped.State, jumped.State)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []uint32{0, 1, 2, 25, 1000, 0x7FFFFFFF, 0xFFFFFFFF}
+
+	for _, n := range tests {
+		lcg := LCG{0x0A0F0320}
+		lcg.Advance(uint64(n))
+		if actual := Distance(0x0A0F0320, lcg.State); actual != n {
+			t.Fatalf("expected distance %d, got %d", n, actual)
 		}
 	}
 }
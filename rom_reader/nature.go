@@ -0,0 +1,139 @@
+package rom_reader
+
+// one of the six stats, in the same order as the fields of Stats
+type Stat uint8
+
+const (
+	StatHp Stat = iota
+	StatAttack
+	StatDefense
+	StatSpAttack
+	StatSpDefense
+	StatSpeed
+)
+
+// every stat, in the same order as the fields of Stats
+var AllStats = []Stat{StatHp, StatAttack, StatDefense, StatSpAttack, StatSpDefense, StatSpeed}
+
+func (s Stat) String() string {
+	switch s {
+	case StatHp:
+		return "HP"
+	case StatAttack:
+		return "Attack"
+	case StatDefense:
+		return "Defense"
+	case StatSpAttack:
+		return "Sp. Atk"
+	case StatSpDefense:
+		return "Sp. Def"
+	case StatSpeed:
+		return "Speed"
+	default:
+		return "Unknown"
+	}
+}
+
+func (s Stats) Get(stat Stat) uint {
+	switch stat {
+	case StatHp:
+		return s.Hp
+	case StatAttack:
+		return s.Attack
+	case StatDefense:
+		return s.Defense
+	case StatSpAttack:
+		return s.SpAttack
+	case StatSpDefense:
+		return s.SpDefense
+	case StatSpeed:
+		return s.Speed
+	default:
+		return 0
+	}
+}
+
+type Nature struct {
+	Index     uint8
+	Name      string
+	Increased Stat
+	Decreased Stat
+}
+
+var natureNames [25]string = [25]string{
+	"Hardy",
+	"Lonely",
+	"Brave",
+	"Adamant",
+	"Naughty",
+	"Bold",
+	"Docile",
+	"Relaxed",
+	"Impish",
+	"Lax",
+	"Timid",
+	"Hasty",
+	"Serious",
+	"Jolly",
+	"Naive",
+	"Modest",
+	"Mild",
+	"Quiet",
+	"Bashful",
+	"Rash",
+	"Calm",
+	"Gentle",
+	"Sassy",
+	"Careful",
+	"Quirky",
+}
+
+// the nature index is 5 * increased + decreased, counting stats in this order
+var natureStats = [5]Stat{StatAttack, StatDefense, StatSpeed, StatSpAttack, StatSpDefense}
+
+// all natures, indexed by personality value % 25
+var Natures [25]Nature = func() [25]Nature {
+	var res [25]Nature
+	for i := range res {
+		res[i] = Nature{uint8(i), natureNames[i], natureStats[i/5], natureStats[i%5]}
+	}
+	return res
+}()
+
+func NatureFromPID(pid uint32) Nature {
+	return Natures[pid%25]
+}
+
+// Returns the nature called `name`. ok is false if there is none
+func NatureByName(name string) (nature Nature, ok bool) {
+	for _, n := range Natures {
+		if n.Name == name {
+			return n, true
+		}
+	}
+	return Nature{}, false
+}
+
+// neutral natures raise and lower the same stat, which cancels out
+func (n Nature) IsNeutral() bool {
+	return n.Increased == n.Decreased
+}
+
+// Multiplier applied to `stat`, in tenths: 11 for the increased stat,
+// 9 for the decreased one and 10 otherwise. HP is never affected
+func (n Nature) Multiplier(stat Stat) uint {
+	switch {
+	case n.IsNeutral() || stat == StatHp:
+		return 10
+	case stat == n.Increased:
+		return 11
+	case stat == n.Decreased:
+		return 9
+	default:
+		return 10
+	}
+}
+
+func (n Nature) String() string {
+	return n.Name
+}
@@ -0,0 +1,63 @@
+package rom_reader
+
+import "testing"
+
+func TestNatureFromPID(t *testing.T) {
+	cases := []struct {
+		pid       uint32
+		name      string
+		increased Stat
+		decreased Stat
+	}{
+		{0x94DFB7DB, "Jolly", StatSpeed, StatSpAttack},
+		{1, "Lonely", StatAttack, StatDefense},
+		{15, "Modest", StatSpAttack, StatAttack},
+		{22, "Sassy", StatSpDefense, StatSpeed},
+	}
+
+	for _, c := range cases {
+		nature := NatureFromPID(c.pid)
+		if nature.Name != c.name || nature.Increased != c.increased || nature.Decreased != c.decreased {
+			t.Fatalf("pid 0x%x: expected %s (+%s -%s), got %+v", c.pid, c.name, c.increased, c.decreased, nature)
+		}
+
+		if nature.IsNeutral() {
+			t.Fatalf("expected %s not to be neutral", nature)
+		}
+	}
+}
+
+func TestNeutralNatures(t *testing.T) {
+	for _, name := range []string{"Hardy", "Docile", "Serious", "Bashful", "Quirky"} {
+		nature, ok := NatureByName(name)
+		if !ok {
+			t.Fatalf("nature %s not found", name)
+		}
+
+		if !nature.IsNeutral() {
+			t.Fatalf("expected %s to be neutral", name)
+		}
+
+		if nature.Multiplier(nature.Increased) != 10 {
+			t.Fatalf("expected %s not to modify %s", name, nature.Increased)
+		}
+	}
+}
+
+func TestNatureMultiplier(t *testing.T) {
+	adamant, _ := NatureByName("Adamant")
+	expected := map[Stat]uint{
+		StatHp:        10,
+		StatAttack:    11,
+		StatDefense:   10,
+		StatSpAttack:  9,
+		StatSpDefense: 10,
+		StatSpeed:     10,
+	}
+
+	for stat, multiplier := range expected {
+		if actual := adamant.Multiplier(stat); actual != multiplier {
+			t.Fatalf("%s: expected %d, got %d", stat, multiplier, actual)
+		}
+	}
+}
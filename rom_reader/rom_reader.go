@@ -4,9 +4,11 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
-	"log"
+
+	"time"
 
 	"github.com/dingdongg/pkmn-platinum-rom-parser/char_encoder"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/data"
 	"github.com/dingdongg/pkmn-platinum-rom-parser/prng"
 )
 
@@ -29,14 +31,95 @@ type BattleStat struct {
 	Stats Stats
 }
 
+type ContestStats struct {
+	Cool   uint8
+	Beauty uint8
+	Cute   uint8
+	Smart  uint8
+	Tough  uint8
+	Sheen  uint8
+}
+
+// bitfield of the markings shown in the PC
+type Markings uint8
+
+const (
+	MarkingCircle Markings = 1 << iota
+	MarkingTriangle
+	MarkingSquare
+	MarkingHeart
+	MarkingStar
+	MarkingDiamond
+)
+
+func (m Markings) Has(marking Markings) bool {
+	return m&marking != 0
+}
+
+type Gender uint8
+
+const (
+	Male Gender = iota
+	Female
+	Genderless
+)
+
+func (g Gender) String() string {
+	switch g {
+	case Male:
+		return "Male"
+	case Female:
+		return "Female"
+	default:
+		return "Genderless"
+	}
+}
+
 type Pokemon struct {
-	PokedexId uint16
-	Name      string
+	Personality uint32
+	PokedexId   uint16
+	SpeciesName string
+	Name        string
 	BattleStat
-	HeldItemId uint16 // just return the in-memory value for now, figure out the mapping later
-	Nature     string
-	AbilityId  uint
-	EVs        Stats
+	HeldItemId       uint16
+	HeldItemName     string
+	Nature           Nature
+	AbilityId        uint
+	AbilityName      string
+	EVs              Stats
+	OTId             uint16
+	OTSecretId       uint16
+	Experience       uint32
+	Friendship       uint8
+	Markings         Markings
+	Language         uint8
+	Contest          ContestStats
+	SinnohRibbonSet1 uint32
+	SinnohRibbonSet2 uint32
+	Moves            [4]uint16
+	MoveNames        [4]string
+	MovePP           [4]uint8
+	MovePPUps        [4]uint8
+	IVs              Stats
+	IsEgg            bool
+	IsNicknamed      bool
+	HoennRibbonSet   uint32
+	FatefulEncounter bool
+	Gender           Gender
+	Form             uint8
+	PtEggLocation    uint16
+	PtMetLocation    uint16
+	OTName           string
+	EggDate          time.Time // zero for pokemon that did not hatch from an egg
+	MetDate          time.Time
+	EggLocation      uint16
+	MetLocation      uint16
+	Pokerus          uint8
+	PokeBall         uint8
+	MetLevel         uint8
+	OTGender         Gender
+	EncounterType    uint8
+	HGSSPokeBall     uint8
 }
 
 const (
@@ -47,34 +130,22 @@ const (
 )
 
 const BLOCK_SIZE_BYTES uint = 32
+const PARTY_SIZE = 6
 const PARTY_POKEMON_SIZE uint = 236
+const BOX_POKEMON_SIZE uint = 136
+const BATTLE_STATS_OFFSET uint = 0x88
 
-var natureTable [25]string = [25]string{
-	"Hardy",
-	"Lonely",
-	"Brave",
-	"Adamant",
-	"Naughty",
-	"Bold",
-	"Docile",
-	"Relaxed",
-	"Impish",
-	"Lax",
-	"Timid",
-	"Hasty",
-	"Serious",
-	"Jolly",
-	"Naive",
-	"Modest",
-	"Mild",
-	"Quiet",
-	"Bashful",
-	"Rash",
-	"Calm",
-	"Gentle",
-	"Sassy",
-	"Careful",
-	"Quirky",
+var ErrOutOfRange = errors.New("pokemon data out of range")
+var ErrInvalidBlock = errors.New("invalid block index")
+
+// returned when the decrypted pokemon data does not match its stored checksum
+type ChecksumError struct {
+	Expected uint16
+	Actual   uint16
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("pokemon checksum invalid: expected 0x%x, got 0x%x", e.Expected, e.Actual)
 }
 
 // populated with results from the shuffler package!
@@ -107,14 +178,88 @@ var unshuffleTable [24]blockOrder = [24]blockOrder{
 
 // `ciphertext` must be a slice with the first byte
 // referring to the first pokemon data structure
-func GetPokemon(ciphertext []byte, partyIndex uint) Pokemon {
+func GetPokemon(ciphertext []byte, partyIndex uint) (Pokemon, error) {
 	offset := partyIndex * PARTY_POKEMON_SIZE
+	if offset+PARTY_POKEMON_SIZE > uint(len(ciphertext)) {
+		return Pokemon{}, ErrOutOfRange
+	}
 
-	personality := binary.LittleEndian.Uint32(ciphertext[offset : offset+4])
-	checksum := binary.LittleEndian.Uint16(ciphertext[offset+6 : offset+8])
+	slot := ciphertext[offset : offset+PARTY_POKEMON_SIZE]
+	if isBlank(slot) {
+		return Pokemon{}, nil
+	}
 
-	rand := prng.Init(checksum, personality)
-	return decryptPokemon(rand, ciphertext[offset:])
+	plaintext, err := Decrypt(slot)
+	if err != nil {
+		return Pokemon{}, err
+	}
+
+	return decodePokemon(plaintext), nil
+}
+
+// `ciphertext` must be a slice with the first byte
+// referring to the first pokemon data structure in a PC box.
+// Box pokemon have no battle stats, so BattleStat is left zeroed
+func GetBoxPokemon(ciphertext []byte, slotIndex uint) (Pokemon, error) {
+	offset := slotIndex * BOX_POKEMON_SIZE
+	if offset+BOX_POKEMON_SIZE > uint(len(ciphertext)) {
+		return Pokemon{}, ErrOutOfRange
+	}
+
+	slot := ciphertext[offset : offset+BOX_POKEMON_SIZE]
+	if isBlank(slot) {
+		return Pokemon{}, nil
+	}
+
+	plaintext, err := DecryptBoxPokemon(slot)
+	if err != nil {
+		return Pokemon{}, err
+	}
+
+	return decodePokemon(plaintext), nil
+}
+
+// unused party and box slots are zeroed out rather than holding encrypted data
+func isBlank(buf []byte) bool {
+	for _, b := range buf {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Pokemon) IsEmpty() bool {
+	return p.PokedexId == 0
+}
+
+// a pokemon is shiny when its shiny value is below this threshold
+const SHINY_THRESHOLD = 8
+
+// XOR of the trainer ID, secret ID and both halves of the personality value
+func ShinyValue(pid uint32, tid uint16, sid uint16) uint16 {
+	return tid ^ sid ^ uint16(pid>>16) ^ uint16(pid)
+}
+
+func IsShiny(pid uint32, tid uint16, sid uint16) bool {
+	return ShinyValue(pid, tid, sid) < SHINY_THRESHOLD
+}
+
+// Returns every secret ID that makes `pid` shiny for a trainer with ID `tid`
+func ShinySecretIds(pid uint32, tid uint16) []uint16 {
+	var res []uint16
+	base := tid ^ uint16(pid>>16) ^ uint16(pid)
+
+	for v := uint16(0); v < SHINY_THRESHOLD; v++ {
+		res = append(res, base^v)
+	}
+
+	return res
+}
+
+// shininess depends on the original trainer, not on who holds the pokemon now
+func (p Pokemon) IsShiny() bool {
+	return !p.IsEmpty() && IsShiny(p.Personality, p.OTId, p.OTSecretId)
 }
 
 // block is one of 0, 1, 2, 3
@@ -135,82 +280,151 @@ func getPokemonBlock(buf []byte, block uint, personality uint32) ([]byte, error)
 		return blockChunk, nil
 	}
 
-	return make([]byte, 0), errors.New("invalid block index")
+	return make([]byte, 0), ErrInvalidBlock
 }
 
-// first block of ciphertext points to offset 0x88 in a party pokemon block
-// TODO: needs some validation/testing
-func getPokemonBattleStats(ciphertext []byte, personality uint32) BattleStat {
-	bsprng := prng.InitBattleStatPRNG(personality)
-	var plaintext []byte
-
-	for i := 0; i < 0x14; i += 2 {
-		decrypted := bsprng.Next() ^ binary.LittleEndian.Uint16(ciphertext[i:i+2])
-		plaintext = append(plaintext, byte(decrypted&0xFF), byte((decrypted>>8)&0xFF))
+// XORs the words of buf[start:end] with consecutive PRNG outputs.
+// encryption and decryption are the same operation
+func xorWords(buf []byte, start uint, end uint, next func() uint16) {
+	for i := start; i < end; i += 2 {
+		word := binary.LittleEndian.Uint16(buf[i:i+2]) ^ next()
+		binary.LittleEndian.PutUint16(buf[i:i+2], word)
 	}
+}
 
-	stats := Stats{
-		uint(binary.LittleEndian.Uint16(plaintext[0x8:0xA])),
-		uint(binary.LittleEndian.Uint16(plaintext[0xA:0xC])),
-		uint(binary.LittleEndian.Uint16(plaintext[0xC:0xE])),
-		uint(binary.LittleEndian.Uint16(plaintext[0x10:0x12])),
-		uint(binary.LittleEndian.Uint16(plaintext[0x12:0x14])),
-		uint(binary.LittleEndian.Uint16(plaintext[0xE:0x10])),
+// sum of the 16-bit words in blocks A-D; block order does not affect the result
+func dataChecksum(plaintext []byte) uint16 {
+	sum := uint16(0)
+	for i := uint(0x8); i < BATTLE_STATS_OFFSET; i += 2 {
+		sum += binary.LittleEndian.Uint16(plaintext[i : i+2])
 	}
+	return sum
+}
 
-	return BattleStat{uint(plaintext[4]), stats}
+// Decrypts the party pokemon at the start of `ciphertext` and returns its
+// plaintext with blocks A-D moved back to their original ABCD order.
+// The input buffer is left untouched
+func Decrypt(ciphertext []byte) ([]byte, error) {
+	return decrypt(ciphertext, PARTY_POKEMON_SIZE)
 }
 
-func decryptPokemon(prng prng.PRNG, ciphertext []byte) Pokemon {
-	plaintext_buf := ciphertext[:8]
-	plaintext_sum := uint16(0)
+// Same as Decrypt, for the 136-byte format used in PC boxes
+func DecryptBoxPokemon(ciphertext []byte) ([]byte, error) {
+	return decrypt(ciphertext, BOX_POKEMON_SIZE)
+}
 
-	// 1. XOR to get plaintext words
-	for i := 0x8; i < 0x87; i += 0x2 {
-		word := binary.LittleEndian.Uint16(ciphertext[i : i+2])
-		plaintext := word ^ prng.Next()
-		plaintext_sum += plaintext
-		littleByte := byte(plaintext & 0x00FF)
-		bigByte := byte((plaintext >> 8) & 0x00FF)
-		plaintext_buf = append(plaintext_buf, littleByte, bigByte)
+// `size` is either PARTY_POKEMON_SIZE or BOX_POKEMON_SIZE; only the
+// former has an encrypted battle stats section
+func decrypt(ciphertext []byte, size uint) ([]byte, error) {
+	if uint(len(ciphertext)) < size {
+		return nil, ErrOutOfRange
 	}
 
-	if plaintext_sum == prng.Checksum {
-		fmt.Println("checksum is valid!")
-	} else {
-		fmt.Printf("Checksum invalid. expected 0x%x, got 0x%x\n", prng.Checksum, plaintext_sum)
+	shuffled := append([]byte{}, ciphertext[:size]...)
+	personality := binary.LittleEndian.Uint32(shuffled[0:4])
+	checksum := binary.LittleEndian.Uint16(shuffled[6:8])
+
+	rand := prng.Init(checksum, personality)
+	xorWords(shuffled, 0x8, BATTLE_STATS_OFFSET, rand.Next)
+
+	if sum := dataChecksum(shuffled); sum != checksum {
+		return nil, &ChecksumError{checksum, sum}
 	}
 
-	blockA, err := getPokemonBlock(plaintext_buf, A, prng.Personality)
-	if err != nil {
-		log.Fatal("Unexpected error while parsing block A: ", err)
+	if size == PARTY_POKEMON_SIZE {
+		bsprng := prng.InitBattleStatPRNG(personality)
+		xorWords(shuffled, BATTLE_STATS_OFFSET, PARTY_POKEMON_SIZE, bsprng.Next)
 	}
 
-	blockC, err := getPokemonBlock(plaintext_buf, C, prng.Personality)
-	if err != nil {
-		log.Fatal("Unexpected error while parsing block C: ", err)
+	plaintext := append([]byte{}, shuffled...)
+	for _, b := range []uint{A, B, C, D} {
+		block, err := getPokemonBlock(shuffled, b, personality)
+		if err != nil {
+			return nil, err
+		}
+
+		start := 0x8 + b*BLOCK_SIZE_BYTES
+		copy(plaintext[start:start+BLOCK_SIZE_BYTES], block)
 	}
 
-	dexId := binary.LittleEndian.Uint16(blockA[:2])
-	heldItem := binary.LittleEndian.Uint16(blockA[2:4])
-	nature := natureTable[prng.Personality%25]
-	ability := binary.LittleEndian.Uint16(blockA[0xD:0xF])
+	return plaintext, nil
+}
+
+// Inverse of Decrypt. Recomputes the checksum of `plaintext`, shuffles
+// blocks A-D according to its personality value and encrypts the result
+func Encrypt(plaintext []byte) ([]byte, error) {
+	if uint(len(plaintext)) < PARTY_POKEMON_SIZE {
+		return nil, ErrOutOfRange
+	}
 
-	pokemonNameLength := 22
-	name := ""
+	ciphertext := append([]byte{}, plaintext[:PARTY_POKEMON_SIZE]...)
+	personality := binary.LittleEndian.Uint32(ciphertext[0:4])
+	checksum := dataChecksum(ciphertext)
+	binary.LittleEndian.PutUint16(ciphertext[6:8], checksum)
 
-	for i := 0; i < pokemonNameLength; i += 2 {
-		charIndex := binary.LittleEndian.Uint16(blockC[i : i+2])
-		str, err := char_encoder.Char(charIndex)
+	for _, b := range []uint{A, B, C, D} {
+		block, err := getPokemonBlock(ciphertext, b, personality)
 		if err != nil {
-			break
+			return nil, err
 		}
-		name += str
+
+		start := 0x8 + b*BLOCK_SIZE_BYTES
+		copy(block, plaintext[start:start+BLOCK_SIZE_BYTES])
+	}
+
+	rand := prng.Init(checksum, personality)
+	xorWords(ciphertext, 0x8, BATTLE_STATS_OFFSET, rand.Next)
+
+	bsprng := prng.InitBattleStatPRNG(personality)
+	xorWords(ciphertext, BATTLE_STATS_OFFSET, PARTY_POKEMON_SIZE, bsprng.Next)
+
+	return ciphertext, nil
+}
+
+// first byte of plaintext points to offset 0x88 in a decrypted party pokemon block
+func getPokemonBattleStats(plaintext []byte) BattleStat {
+	stats := Stats{
+		uint(binary.LittleEndian.Uint16(plaintext[0x8:0xA])),
+		uint(binary.LittleEndian.Uint16(plaintext[0xA:0xC])),
+		uint(binary.LittleEndian.Uint16(plaintext[0xC:0xE])),
+		uint(binary.LittleEndian.Uint16(plaintext[0x10:0x12])),
+		uint(binary.LittleEndian.Uint16(plaintext[0x12:0x14])),
+		uint(binary.LittleEndian.Uint16(plaintext[0xE:0x10])),
 	}
 
-	fmt.Printf("Pokemon: '%s'\n", name)
+	return BattleStat{uint(plaintext[4]), stats}
+}
+
+// `plaintext` must be unshuffled, as returned by Decrypt or DecryptBoxPokemon
+func decodePokemon(plaintext []byte) Pokemon {
+	personality := binary.LittleEndian.Uint32(plaintext[0:4])
+	blockA := plaintext[0x8 : 0x8+BLOCK_SIZE_BYTES]
+	blockB := plaintext[0x28 : 0x28+BLOCK_SIZE_BYTES]
+	blockC := plaintext[0x48 : 0x48+BLOCK_SIZE_BYTES]
+	blockD := plaintext[0x68 : 0x68+BLOCK_SIZE_BYTES]
 
-	battleStats := getPokemonBattleStats(ciphertext[0x88:], prng.Personality)
+	pokemonNameLength := 22
+	otNameLength := 16
+	name := char_encoder.DecodeBytes(blockC[:pokemonNameLength])
+	otName := char_encoder.DecodeBytes(blockD[:otNameLength])
+
+	var battleStats BattleStat
+	if uint(len(plaintext)) >= PARTY_POKEMON_SIZE {
+		battleStats = getPokemonBattleStats(plaintext[BATTLE_STATS_OFFSET:])
+	}
+
+	var moves [4]uint16
+	var moveNames [4]string
+	var movePP, movePPUps [4]uint8
+	for i := 0; i < 4; i++ {
+		moves[i] = binary.LittleEndian.Uint16(blockB[2*i : 2*i+2])
+		moveNames[i] = data.Move(moves[i]).Name
+		movePP[i] = blockB[0x8+i]
+		movePPUps[i] = blockB[0xC+i]
+	}
+
+	ivWord := binary.LittleEndian.Uint32(blockB[0x10:0x14])
+	flags := blockB[0x18]
 
 	hpEVOffset := 0x10
 	attackEVOffset := 0x11
@@ -219,28 +433,21 @@ func decryptPokemon(prng prng.PRNG, ciphertext []byte) Pokemon {
 	specialAtkEVOffset := 0x14
 	specialDefEVOffset := 0x15
 
-	fmt.Printf(
-		"Stats:\n\t- HP:  %d\n\t- ATK: %d\n\t- DEF: %d\n\t- SpA: %d\n\t- SpD: %d\n\t- SPE: %d\n",
-		blockA[hpEVOffset], blockA[attackEVOffset],
-		blockA[defenseEVOffset], blockA[specialAtkEVOffset],
-		blockA[specialDefEVOffset], blockA[speedEVOffset],
-	)
-
-	evSum := 0
-	for i := 0; i < 6; i++ {
-		evSum += int(blockA[hpEVOffset+i])
-	}
-
-	fmt.Printf("Total EV Spenditure: %d / 510\n", evSum)
+	pokedexId := binary.LittleEndian.Uint16(blockA[0x0:0x2])
+	heldItemId := binary.LittleEndian.Uint16(blockA[0x2:0x4])
 
 	return Pokemon{
-		dexId,
-		name,
-		battleStats,
-		heldItem,
-		nature,
-		uint(ability),
-		Stats{
+		Personality:  personality,
+		PokedexId:    pokedexId,
+		SpeciesName:  data.Species(pokedexId).Name,
+		Name:         name,
+		BattleStat:   battleStats,
+		HeldItemId:   heldItemId,
+		HeldItemName: data.Item(heldItemId).Name,
+		Nature:       NatureFromPID(personality),
+		AbilityId:    uint(blockA[0xD]),
+		AbilityName:  data.Ability(blockA[0xD]).Name,
+		EVs: Stats{
 			uint(blockA[hpEVOffset]),
 			uint(blockA[attackEVOffset]),
 			uint(blockA[defenseEVOffset]),
@@ -248,5 +455,192 @@ func decryptPokemon(prng prng.PRNG, ciphertext []byte) Pokemon {
 			uint(blockA[specialDefEVOffset]),
 			uint(blockA[speedEVOffset]),
 		},
+		OTId:       binary.LittleEndian.Uint16(blockA[0x4:0x6]),
+		OTSecretId: binary.LittleEndian.Uint16(blockA[0x6:0x8]),
+		Experience: binary.LittleEndian.Uint32(blockA[0x8:0xC]),
+		Friendship: blockA[0xC],
+		Markings:   Markings(blockA[0xE]),
+		Language:   blockA[0xF],
+		Contest: ContestStats{
+			blockA[0x16],
+			blockA[0x17],
+			blockA[0x18],
+			blockA[0x19],
+			blockA[0x1A],
+			blockA[0x1B],
+		},
+		SinnohRibbonSet1: binary.LittleEndian.Uint32(blockA[0x1C:0x20]),
+		SinnohRibbonSet2: binary.LittleEndian.Uint32(blockC[0x18:0x1C]),
+		Moves:            moves,
+		MoveNames:        moveNames,
+		MovePP:           movePP,
+		MovePPUps:        movePPUps,
+		IVs:              UnpackIVs(ivWord),
+		IsEgg:            ivWord&(1<<30) != 0,
+		IsNicknamed:      ivWord&(1<<31) != 0,
+		HoennRibbonSet:   binary.LittleEndian.Uint32(blockB[0x14:0x18]),
+		FatefulEncounter: flags&0x1 != 0,
+		Gender:           genderFromFlags(flags),
+		Form:             flags >> 3,
+		PtEggLocation:    binary.LittleEndian.Uint16(blockB[0x1C:0x1E]),
+		PtMetLocation:    binary.LittleEndian.Uint16(blockB[0x1E:0x20]),
+		OTName:           otName,
+		EggDate:          decodeDate(blockD[0x10:0x13]),
+		MetDate:          decodeDate(blockD[0x13:0x16]),
+		EggLocation:      binary.LittleEndian.Uint16(blockD[0x16:0x18]),
+		MetLocation:      binary.LittleEndian.Uint16(blockD[0x18:0x1A]),
+		Pokerus:          blockD[0x1A],
+		PokeBall:         blockD[0x1B],
+		MetLevel:         blockD[0x1C] & 0x7F,
+		OTGender:         Gender(blockD[0x1C] >> 7),
+		EncounterType:    blockD[0x1D],
+		HGSSPokeBall:     blockD[0x1E],
+	}
+}
+
+// dates are stored as 3 bytes: years since 2000, month, day
+func decodeDate(buf []byte) time.Time {
+	if buf[0] == 0 && buf[1] == 0 && buf[2] == 0 {
+		return time.Time{}
 	}
+
+	return time.Date(2000+int(buf[0]), time.Month(buf[1]), int(buf[2]), 0, 0, 0, 0, time.UTC)
+}
+
+// location where the pokemon was met. Platinum stores locations that
+// don't exist in Diamond/Pearl separately, and sets the original field to
+// "Faraway place" for backwards compatibility
+func (p Pokemon) MetLocationId() uint16 {
+	if p.PtMetLocation != 0 {
+		return p.PtMetLocation
+	}
+	return p.MetLocation
+}
+
+// see MetLocationId
+func (p Pokemon) EggLocationId() uint16 {
+	if p.PtEggLocation != 0 {
+		return p.PtEggLocation
+	}
+	return p.EggLocation
+}
+
+var unownForms = []string{
+	"A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N",
+	"O", "P", "Q", "R", "S", "T", "U", "V", "W", "X", "Y", "Z", "!", "?",
+}
+
+var arceusForms = []string{
+	"Normal", "Fighting", "Flying", "Poison", "Ground", "Rock", "Bug", "Ghost", "Steel",
+	"???", "Fire", "Water", "Grass", "Electric", "Psychic", "Ice", "Dragon", "Dark",
+}
+
+// names of the alternate forms, indexed by the form stored in block B
+var formNames = map[uint16][]string{
+	172: {"Normal", "Spiky-eared"},
+	201: unownForms,
+	386: {"Normal", "Attack", "Defense", "Speed"},
+	412: {"Plant", "Sandy", "Trash"},
+	413: {"Plant", "Sandy", "Trash"},
+	422: {"West", "East"},
+	423: {"West", "East"},
+	479: {"Normal", "Heat", "Wash", "Frost", "Fan", "Mow"},
+	487: {"Altered", "Origin"},
+	492: {"Land", "Sky"},
+	493: arceusForms,
+}
+
+// name of the pokemon's alternate form, or "" for species without forms
+func (p Pokemon) FormName() string {
+	forms, ok := formNames[p.PokedexId]
+	if !ok || int(p.Form) >= len(forms) {
+		return ""
+	}
+
+	return forms[p.Form]
+}
+
+// IVs are packed into the lower 30 bits, 5 bits per stat,
+// in the order HP, Atk, Def, Spe, SpA, SpD
+func UnpackIVs(ivWord uint32) Stats {
+	iv := func(i uint) uint {
+		return uint((ivWord >> (5 * i)) & 0x1F)
+	}
+
+	return Stats{iv(0), iv(1), iv(2), iv(4), iv(5), iv(3)}
+}
+
+// inverse of UnpackIVs; the egg and nickname flags are left unset
+func PackIVs(ivs Stats) uint32 {
+	order := []uint{ivs.Hp, ivs.Attack, ivs.Defense, ivs.Speed, ivs.SpAttack, ivs.SpDefense}
+	res := uint32(0)
+
+	for i, iv := range order {
+		res |= uint32(iv&0x1F) << (5 * i)
+	}
+
+	return res
+}
+
+// bit 1 is set for female pokemon, bit 2 for genderless ones
+func genderFromFlags(flags byte) Gender {
+	if flags&0x4 != 0 {
+		return Genderless
+	}
+
+	if flags&0x2 != 0 {
+		return Female
+	}
+
+	return Male
+}
+
+// the gender a pokemon of the given gender ratio must have for personality value `pid`
+func GenderFromPID(pid uint32, ratio data.GenderRatio) Gender {
+	switch ratio {
+	case data.RatioGenderless:
+		return Genderless
+	case data.RatioMaleOnly:
+		return Male
+	case data.RatioFemaleOnly:
+		return Female
+	}
+
+	if uint8(pid) < uint8(ratio) {
+		return Female
+	}
+
+	return Male
+}
+
+// Gender implied by the personality value and the species' gender ratio.
+// ok is false if the species is unknown
+func (p Pokemon) PIDGender() (gender Gender, ok bool) {
+	ratio, ok := data.GenderRatioOf(p.PokedexId)
+	if !ok {
+		return 0, false
+	}
+
+	return GenderFromPID(p.Personality, ratio), true
+}
+
+// true if the stored gender disagrees with the personality value,
+// which the game never produces on its own
+func (p Pokemon) GenderMismatch() bool {
+	gender, ok := p.PIDGender()
+	return ok && gender != p.Gender
+}
+
+func (s Stats) Total() uint {
+	return s.Hp + s.Attack + s.Defense + s.SpAttack + s.SpDefense + s.Speed
+}
+
+// pokemon format specifier; prints the same summary that used to be
+// written to stdout while parsing
+func (p Pokemon) String() string {
+	return fmt.Sprintf(
+		"Pokemon: '%s'\nStats:\n\t- HP:  %d\n\t- ATK: %d\n\t- DEF: %d\n\t- SpA: %d\n\t- SpD: %d\n\t- SPE: %d\nTotal EV Spenditure: %d / 510",
+		p.Name, p.EVs.Hp, p.EVs.Attack, p.EVs.Defense,
+		p.EVs.SpAttack, p.EVs.SpDefense, p.EVs.Speed, p.EVs.Total(),
+	)
 }
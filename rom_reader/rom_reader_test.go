@@ -1,9 +1,14 @@
 package rom_reader
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/dingdongg/pkmn-platinum-rom-parser/data"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -85,22 +90,412 @@ func TestGetPokemon(t *testing.T) {
 		t.Fatal("Unexpected error ", err)
 	}
 
-	firstPokemon := GetPokemon(savefile[:], 0)
+	firstPokemon, err := GetPokemon(savefile[:], 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
 
 	expectedPokemon := Pokemon{
-		461,
-		"WEAVILE",
-		BattleStat{
+		PokedexId:   461,
+		SpeciesName: "Weavile",
+		Name:        "WEAVILE",
+		BattleStat: BattleStat{
 			58,
 			Stats{163, 181, 93, 63, 106, 215},
 		},
-		0,
-		"Jolly",
-		46,
-		Stats{0, 255, 0, 0, 3, 252},
+		HeldItemId:       0,
+		Nature:           Natures[13],
+		AbilityId:        46,
+		AbilityName:      "Pressure",
+		EVs:              Stats{0, 255, 0, 0, 3, 252},
+		Personality:      0x94DFB7DB,
+		OTId:             26241,
+		OTSecretId:       11961,
+		Experience:       191385,
+		Friendship:       255,
+		Language:         2,
+		SinnohRibbonSet1: 0x1,
+		Moves:            [4]uint16{400, 420, 280, 8},
+		MoveNames:        [4]string{"Night Slash", "Ice Shard", "Brick Break", "Ice Punch"},
+		MovePP:           [4]uint8{15, 30, 15, 15},
+		IVs:              Stats{25, 1, 23, 25, 5, 17},
+		HoennRibbonSet:   0x1000000,
+		Gender:           Male,
+		PtMetLocation:    74,
+		OTName:           "DONGGYU",
+		MetDate:          time.Date(2024, time.April, 29, 0, 0, 0, 0, time.UTC),
+		MetLocation:      74,
+		PokeBall:         4,
+		MetLevel:         35,
+		EncounterType:    2,
 	}
 
 	if !cmp.Equal(firstPokemon, expectedPokemon) {
 		t.Fatalf("expected %+v, but got %+v\n", expectedPokemon, firstPokemon)
 	}
 }
+
+func TestGetPokemonDoesNotMutateInput(t *testing.T) {
+	savefile, err := os.ReadFile("./mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	original := append([]byte{}, savefile...)
+	if _, err := GetPokemon(savefile, 0); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if !bytes.Equal(savefile, original) {
+		t.Fatal("GetPokemon modified the input buffer")
+	}
+}
+
+func TestGetPokemonOutOfRange(t *testing.T) {
+	savefile, err := os.ReadFile("./mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	_, err = GetPokemon(savefile, 1)
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestGetPokemonChecksumMismatch(t *testing.T) {
+	savefile, err := os.ReadFile("./mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	savefile[0x10] ^= 0xFF
+
+	_, err = GetPokemon(savefile, 0)
+	var checksumErr *ChecksumError
+	if !errors.As(err, &checksumErr) {
+		t.Fatalf("expected a ChecksumError, got %v", err)
+	}
+}
+
+func TestEncryptDecryptRoundTrip(t *testing.T) {
+	savefile, err := os.ReadFile("./mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	plaintext, err := Decrypt(savefile)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	ciphertext, err := Encrypt(plaintext)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if !bytes.Equal(ciphertext, savefile) {
+		t.Fatal("re-encrypted data does not match the original ciphertext")
+	}
+}
+
+func TestDecryptUnshufflesBlocks(t *testing.T) {
+	savefile, err := os.ReadFile("./mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	plaintext, err := Decrypt(savefile)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	// block A is always first once unshuffled; WEAVILE is #461
+	dexId := uint16(plaintext[0x8]) | uint16(plaintext[0x9])<<8
+	if dexId != 461 {
+		t.Fatalf("expected 461, got %d", dexId)
+	}
+}
+
+func TestGetBoxPokemon(t *testing.T) {
+	savefile, err := os.ReadFile("./mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	// the first 136 bytes of a party pokemon are its PC box representation
+	pokemon, err := GetBoxPokemon(savefile[:BOX_POKEMON_SIZE], 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	party, err := GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	party.BattleStat = BattleStat{}
+	if !cmp.Equal(pokemon, party) {
+		t.Fatalf("expected %+v, but got %+v\n", party, pokemon)
+	}
+}
+
+func TestGetBoxPokemonEmptySlot(t *testing.T) {
+	pokemon, err := GetBoxPokemon(make([]byte, BOX_POKEMON_SIZE*2), 1)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if !pokemon.IsEmpty() {
+		t.Fatalf("expected an empty slot, got %+v", pokemon)
+	}
+}
+
+func TestGetPokemonBlockA(t *testing.T) {
+	plaintext := make([]byte, PARTY_POKEMON_SIZE)
+	binary.LittleEndian.PutUint32(plaintext[0x0:0x4], 0x12345678)
+
+	blockA := []byte{
+		0x85, 0x01, // species: 389
+		0xEA, 0x00, // held item: 234
+		0x39, 0x30, // OT ID: 12345
+		0x31, 0xD4, // OT secret ID: 54321
+		0x40, 0xE2, 0x01, 0x00, // experience: 123456
+		0x46,                               // friendship: 70
+		0x41,                               // ability: 65
+		0x21,                               // markings: circle, diamond
+		0x02,                               // language: English
+		0x04, 0x08, 0x0F, 0x10, 0x17, 0x2A, // EVs: HP, Atk, Def, Spe, SpA, SpD
+		0x0A, 0x14, 0x1E, 0x28, 0x32, 0xFF, // contest: cool, beauty, cute, smart, tough, sheen
+		0x01, 0x00, 0x00, 0x80, // sinnoh ribbons
+	}
+	copy(plaintext[0x8:0x28], blockA)
+
+	ciphertext, err := Encrypt(plaintext)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon, err := GetPokemon(ciphertext, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	expected := Pokemon{
+		Personality:      0x12345678,
+		PokedexId:        389,
+		SpeciesName:      "Torterra",
+		HeldItemId:       234,
+		HeldItemName:     "Leftovers",
+		Nature:           NatureFromPID(0x12345678),
+		AbilityId:        65,
+		AbilityName:      "Overgrow",
+		EVs:              Stats{4, 8, 15, 23, 42, 16},
+		OTId:             12345,
+		OTSecretId:       54321,
+		Experience:       123456,
+		Friendship:       70,
+		Markings:         MarkingCircle | MarkingDiamond,
+		Language:         2,
+		Contest:          ContestStats{10, 20, 30, 40, 50, 255},
+		SinnohRibbonSet1: 0x80000001,
+	}
+
+	if !cmp.Equal(pokemon, expected) {
+		t.Fatalf("expected %+v, but got %+v\n", expected, pokemon)
+	}
+}
+
+func TestGetPokemonBlockB(t *testing.T) {
+	plaintext := make([]byte, PARTY_POKEMON_SIZE)
+	binary.LittleEndian.PutUint16(plaintext[0x8:0xA], 201)
+
+	ivs := Stats{31, 30, 29, 28, 27, 26}
+	blockB := plaintext[0x28:0x48]
+	binary.LittleEndian.PutUint16(blockB[0x0:0x2], 237)
+	blockB[0x8] = 24
+	blockB[0xC] = 3
+	binary.LittleEndian.PutUint32(blockB[0x10:0x14], PackIVs(ivs)|(1<<31))
+	blockB[0x18] = (25 << 3) | 0x2 | 0x1 // form Z, female, fateful encounter
+
+	ciphertext, err := Encrypt(plaintext)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon, err := GetPokemon(ciphertext, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if pokemon.Moves[0] != 237 || pokemon.MovePP[0] != 24 || pokemon.MovePPUps[0] != 3 {
+		t.Fatalf("unexpected move data: %v %v %v", pokemon.Moves, pokemon.MovePP, pokemon.MovePPUps)
+	}
+
+	if !cmp.Equal(pokemon.IVs, ivs) {
+		t.Fatalf("expected IVs %+v, got %+v", ivs, pokemon.IVs)
+	}
+
+	if pokemon.IsEgg || !pokemon.IsNicknamed || !pokemon.FatefulEncounter {
+		t.Fatal("egg, nickname or fateful encounter flag decoded incorrectly")
+	}
+
+	if pokemon.Gender != Female {
+		t.Fatalf("expected Female, got %s", pokemon.Gender)
+	}
+
+	if pokemon.FormName() != "Z" {
+		t.Fatalf("expected form Z, got '%s'", pokemon.FormName())
+	}
+}
+
+func TestPackIVs(t *testing.T) {
+	ivs := Stats{1, 2, 3, 4, 5, 6}
+	if actual := UnpackIVs(PackIVs(ivs)); !cmp.Equal(actual, ivs) {
+		t.Fatalf("expected %+v, got %+v", ivs, actual)
+	}
+}
+
+func TestGetPokemonBlockD(t *testing.T) {
+	plaintext := make([]byte, PARTY_POKEMON_SIZE)
+	binary.LittleEndian.PutUint16(plaintext[0x8:0xA], 175)
+
+	blockB := plaintext[0x28:0x48]
+	binary.LittleEndian.PutUint16(blockB[0x1C:0x1E], 2011)
+
+	blockD := plaintext[0x68:0x88]
+	// "ABC" followed by a terminator
+	binary.LittleEndian.PutUint16(blockD[0x0:0x2], 0x012B)
+	binary.LittleEndian.PutUint16(blockD[0x2:0x4], 0x012C)
+	binary.LittleEndian.PutUint16(blockD[0x4:0x6], 0x012D)
+	binary.LittleEndian.PutUint16(blockD[0x6:0x8], 0xFFFF)
+	copy(blockD[0x10:0x16], []byte{9, 3, 22, 9, 4, 1})
+	binary.LittleEndian.PutUint16(blockD[0x16:0x18], 3002)
+	binary.LittleEndian.PutUint16(blockD[0x18:0x1A], 2000)
+	blockD[0x1A] = 0x13
+	blockD[0x1B] = 4
+	blockD[0x1C] = 0x80 // hatched (met level 0), female OT
+
+	ciphertext, err := Encrypt(plaintext)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon, err := GetPokemon(ciphertext, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if pokemon.OTName != "ABC" || pokemon.OTGender != Female {
+		t.Fatalf("expected female OT 'ABC', got %s OT '%s'", pokemon.OTGender, pokemon.OTName)
+	}
+
+	eggDate := time.Date(2009, time.March, 22, 0, 0, 0, 0, time.UTC)
+	metDate := time.Date(2009, time.April, 1, 0, 0, 0, 0, time.UTC)
+	if !pokemon.EggDate.Equal(eggDate) || !pokemon.MetDate.Equal(metDate) {
+		t.Fatalf("unexpected dates: egg %v, met %v", pokemon.EggDate, pokemon.MetDate)
+	}
+
+	if pokemon.EggLocationId() != 2011 || pokemon.MetLocationId() != 2000 {
+		t.Fatalf("unexpected locations: egg %d, met %d", pokemon.EggLocationId(), pokemon.MetLocationId())
+	}
+
+	if pokemon.Pokerus != 0x13 || pokemon.PokeBall != 4 || pokemon.MetLevel != 0 {
+		t.Fatalf("unexpected pokerus %d, ball %d or met level %d", pokemon.Pokerus, pokemon.PokeBall, pokemon.MetLevel)
+	}
+}
+
+func TestGetPokemonEmptySlot(t *testing.T) {
+	pokemon, err := GetPokemon(make([]byte, PARTY_POKEMON_SIZE), 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if !pokemon.IsEmpty() {
+		t.Fatalf("expected an empty slot, got %+v", pokemon)
+	}
+}
+
+func TestShinyValue(t *testing.T) {
+	// the mock weavile's PID and TID; its real SID does not make it shiny
+	pid := uint32(0x94DFB7DB)
+	tid := uint16(26241)
+
+	sids := ShinySecretIds(pid, tid)
+	if len(sids) != SHINY_THRESHOLD {
+		t.Fatalf("expected %d secret IDs, got %d", SHINY_THRESHOLD, len(sids))
+	}
+
+	for i, sid := range sids {
+		if ShinyValue(pid, tid, sid) != uint16(i) || !IsShiny(pid, tid, sid) {
+			t.Fatalf("secret ID %d does not make 0x%x shiny", sid, pid)
+		}
+	}
+
+	if IsShiny(pid, tid, 11961) || IsShiny(pid, tid, sids[0]^SHINY_THRESHOLD) {
+		t.Fatal("expected pokemon not to be shiny")
+	}
+}
+
+func TestPokemonIsShiny(t *testing.T) {
+	p := Pokemon{PokedexId: 461, Personality: 0x12345678, OTId: 12345}
+	p.OTSecretId = ShinySecretIds(p.Personality, p.OTId)[3]
+
+	if !p.IsShiny() {
+		t.Fatal("expected pokemon to be shiny")
+	}
+
+	p.OTSecretId ^= 0x100
+	if p.IsShiny() {
+		t.Fatal("expected pokemon not to be shiny")
+	}
+
+	if (Pokemon{}).IsShiny() {
+		t.Fatal("empty slot reported as shiny")
+	}
+}
+
+func TestGenderFromPID(t *testing.T) {
+	cases := []struct {
+		pid      uint32
+		ratio    data.GenderRatio
+		expected Gender
+	}{
+		{0x1234567E, data.RatioEven, Female},
+		{0x1234567F, data.RatioEven, Male},
+		{0x1234561E, data.RatioMale7To1, Female},
+		{0x1234561F, data.RatioMale7To1, Male},
+		{0x123456BE, data.RatioFemale3To1, Female},
+		{0x123456FF, data.RatioFemaleOnly, Female},
+		{0x12345600, data.RatioMaleOnly, Male},
+		{0x12345600, data.RatioGenderless, Genderless},
+	}
+
+	for _, c := range cases {
+		if gender := GenderFromPID(c.pid, c.ratio); gender != c.expected {
+			t.Fatalf("PID 0x%x with ratio %s: expected %s, got %s", c.pid, c.ratio, c.expected, gender)
+		}
+	}
+}
+
+func TestGenderMismatch(t *testing.T) {
+	// weavile has an even gender ratio
+	p := Pokemon{PokedexId: 461, Personality: 0x94DFB7DB, Gender: Male}
+
+	if gender, ok := p.PIDGender(); !ok || gender != Male {
+		t.Fatalf("expected Male, got %s", gender)
+	}
+
+	if p.GenderMismatch() {
+		t.Fatal("unexpected gender mismatch")
+	}
+
+	p.Gender = Female
+	if !p.GenderMismatch() {
+		t.Fatal("expected a gender mismatch")
+	}
+
+	if (Pokemon{PokedexId: 600, Gender: Female}).GenderMismatch() {
+		t.Fatal("unexpected gender mismatch for an unknown species")
+	}
+}
@@ -0,0 +1,245 @@
+package rom_writer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/char_encoder"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+const blockAOffset uint = 0x8
+const blockBOffset uint = 0x28
+const blockCOffset uint = 0x48
+const blockDOffset uint = 0x68
+const nicknameSize = 22
+const otNameSize = 16
+
+const MAX_EV uint = 255
+const MAX_LEVEL uint = 100
+const MAX_IV uint = 31
+const MAX_PP_UPS uint8 = 3
+const MAX_FORM uint8 = 31
+const MAX_STAT uint = 0xFFFF
+
+// `ciphertext` must be a slice with the first byte
+// referring to the first pokemon data structure.
+// Fields that rom_reader.Pokemon does not expose are carried over from the
+// existing data. Nature is not written since it is derived from the
+// personality value, nor are the species, item, ability and move names
+// looked up from their IDs. Names are only re-encoded when they have changed.
+// Changing the personality value also changes how blocks A-D are shuffled
+func SetPokemon(ciphertext []byte, partyIndex uint, pokemon rom_reader.Pokemon) error {
+	offset := partyIndex * rom_reader.PARTY_POKEMON_SIZE
+	if offset+rom_reader.PARTY_POKEMON_SIZE > uint(len(ciphertext)) {
+		return rom_reader.ErrOutOfRange
+	}
+
+	if err := checkPokemon(pokemon); err != nil {
+		return err
+	}
+
+	slot := ciphertext[offset : offset+rom_reader.PARTY_POKEMON_SIZE]
+	plaintext, err := rom_reader.Decrypt(slot)
+	if err != nil {
+		return err
+	}
+
+	if err := encodePokemon(pokemon, plaintext); err != nil {
+		return err
+	}
+
+	encrypted, err := rom_reader.Encrypt(plaintext)
+	if err != nil {
+		return err
+	}
+
+	copy(slot, encrypted)
+	return nil
+}
+
+// rejects values that do not fit in their in-memory representation
+func checkPokemon(pokemon rom_reader.Pokemon) error {
+	evs := []uint{
+		pokemon.EVs.Hp, pokemon.EVs.Attack, pokemon.EVs.Defense,
+		pokemon.EVs.SpAttack, pokemon.EVs.SpDefense, pokemon.EVs.Speed,
+	}
+
+	for _, ev := range evs {
+		if ev > MAX_EV {
+			return fmt.Errorf("EV %d exceeds %d", ev, MAX_EV)
+		}
+	}
+
+	if pokemon.Level > MAX_LEVEL {
+		return fmt.Errorf("level %d exceeds %d", pokemon.Level, MAX_LEVEL)
+	}
+
+	ivs := []uint{
+		pokemon.IVs.Hp, pokemon.IVs.Attack, pokemon.IVs.Defense,
+		pokemon.IVs.SpAttack, pokemon.IVs.SpDefense, pokemon.IVs.Speed,
+	}
+
+	for _, iv := range ivs {
+		if iv > MAX_IV {
+			return fmt.Errorf("IV %d exceeds %d", iv, MAX_IV)
+		}
+	}
+
+	for _, ppUps := range pokemon.MovePPUps {
+		if ppUps > MAX_PP_UPS {
+			return fmt.Errorf("%d PP Ups exceeds %d", ppUps, MAX_PP_UPS)
+		}
+	}
+
+	if pokemon.Form > MAX_FORM {
+		return fmt.Errorf("form %d does not fit in 5 bits", pokemon.Form)
+	}
+
+	if pokemon.MetLevel > uint8(MAX_LEVEL) {
+		return fmt.Errorf("met level %d exceeds %d", pokemon.MetLevel, MAX_LEVEL)
+	}
+
+	if pokemon.OTGender > rom_reader.Female {
+		return fmt.Errorf("invalid OT gender %d", pokemon.OTGender)
+	}
+
+	for _, date := range []time.Time{pokemon.EggDate, pokemon.MetDate} {
+		if !date.IsZero() && (date.Year() < 2000 || date.Year() > 2255) {
+			return fmt.Errorf("date %s can't be stored", date.Format(time.DateOnly))
+		}
+	}
+
+	for _, stat := range rom_reader.AllStats {
+		if value := pokemon.BattleStat.Stats.Get(stat); value > MAX_STAT {
+			return fmt.Errorf("%s stat %d exceeds %d", stat, value, MAX_STAT)
+		}
+	}
+
+	if pokemon.AbilityId > 0xFF {
+		return fmt.Errorf("ability ID %d does not fit in a byte", pokemon.AbilityId)
+	}
+
+	return nil
+}
+
+// dates are stored as 3 bytes: years since 2000, month, day
+func encodeDate(date time.Time, buf []byte) {
+	if date.IsZero() {
+		buf[0], buf[1], buf[2] = 0, 0, 0
+		return
+	}
+
+	buf[0] = byte(date.Year() - 2000)
+	buf[1] = byte(date.Month())
+	buf[2] = byte(date.Day())
+}
+
+// re-encodes `name` into `buf` unless it already holds it, so that
+// unchanged names keep their original padding
+func encodeName(name string, buf []byte) error {
+	if char_encoder.DecodeBytes(buf) == name {
+		return nil
+	}
+
+	return char_encoder.EncodeBytes(name, buf)
+}
+
+// `plaintext` must hold an unshuffled party pokemon, as returned by rom_reader.Decrypt
+func encodePokemon(pokemon rom_reader.Pokemon, plaintext []byte) error {
+	binary.LittleEndian.PutUint32(plaintext[0:4], pokemon.Personality)
+
+	blockA := plaintext[blockAOffset : blockAOffset+rom_reader.BLOCK_SIZE_BYTES]
+	blockB := plaintext[blockBOffset : blockBOffset+rom_reader.BLOCK_SIZE_BYTES]
+	blockC := plaintext[blockCOffset : blockCOffset+rom_reader.BLOCK_SIZE_BYTES]
+	blockD := plaintext[blockDOffset : blockDOffset+rom_reader.BLOCK_SIZE_BYTES]
+
+	binary.LittleEndian.PutUint16(blockA[0x0:0x2], pokemon.PokedexId)
+	binary.LittleEndian.PutUint16(blockA[0x2:0x4], pokemon.HeldItemId)
+	binary.LittleEndian.PutUint16(blockA[0x4:0x6], pokemon.OTId)
+	binary.LittleEndian.PutUint16(blockA[0x6:0x8], pokemon.OTSecretId)
+	binary.LittleEndian.PutUint32(blockA[0x8:0xC], pokemon.Experience)
+	blockA[0xC] = pokemon.Friendship
+	blockA[0xD] = byte(pokemon.AbilityId)
+	blockA[0xE] = byte(pokemon.Markings)
+	blockA[0xF] = pokemon.Language
+
+	blockA[0x10] = byte(pokemon.EVs.Hp)
+	blockA[0x11] = byte(pokemon.EVs.Attack)
+	blockA[0x12] = byte(pokemon.EVs.Defense)
+	blockA[0x13] = byte(pokemon.EVs.Speed)
+	blockA[0x14] = byte(pokemon.EVs.SpAttack)
+	blockA[0x15] = byte(pokemon.EVs.SpDefense)
+
+	blockA[0x16] = pokemon.Contest.Cool
+	blockA[0x17] = pokemon.Contest.Beauty
+	blockA[0x18] = pokemon.Contest.Cute
+	blockA[0x19] = pokemon.Contest.Smart
+	blockA[0x1A] = pokemon.Contest.Tough
+	blockA[0x1B] = pokemon.Contest.Sheen
+	binary.LittleEndian.PutUint32(blockA[0x1C:0x20], pokemon.SinnohRibbonSet1)
+	binary.LittleEndian.PutUint32(blockC[0x18:0x1C], pokemon.SinnohRibbonSet2)
+
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint16(blockB[2*i:2*i+2], pokemon.Moves[i])
+		blockB[0x8+i] = pokemon.MovePP[i]
+		blockB[0xC+i] = pokemon.MovePPUps[i]
+	}
+
+	ivWord := rom_reader.PackIVs(pokemon.IVs)
+	if pokemon.IsEgg {
+		ivWord |= 1 << 30
+	}
+	if pokemon.IsNicknamed {
+		ivWord |= 1 << 31
+	}
+	binary.LittleEndian.PutUint32(blockB[0x10:0x14], ivWord)
+	binary.LittleEndian.PutUint32(blockB[0x14:0x18], pokemon.HoennRibbonSet)
+
+	flags := pokemon.Form << 3
+	if pokemon.FatefulEncounter {
+		flags |= 0x1
+	}
+	switch pokemon.Gender {
+	case rom_reader.Female:
+		flags |= 0x2
+	case rom_reader.Genderless:
+		flags |= 0x4
+	}
+	blockB[0x18] = flags
+	binary.LittleEndian.PutUint16(blockB[0x1C:0x1E], pokemon.PtEggLocation)
+	binary.LittleEndian.PutUint16(blockB[0x1E:0x20], pokemon.PtMetLocation)
+
+	encodeDate(pokemon.EggDate, blockD[0x10:0x13])
+	encodeDate(pokemon.MetDate, blockD[0x13:0x16])
+	binary.LittleEndian.PutUint16(blockD[0x16:0x18], pokemon.EggLocation)
+	binary.LittleEndian.PutUint16(blockD[0x18:0x1A], pokemon.MetLocation)
+	blockD[0x1A] = pokemon.Pokerus
+	blockD[0x1B] = pokemon.PokeBall
+	blockD[0x1C] = pokemon.MetLevel | byte(pokemon.OTGender)<<7
+	blockD[0x1D] = pokemon.EncounterType
+	blockD[0x1E] = pokemon.HGSSPokeBall
+
+	battleStats := plaintext[rom_reader.BATTLE_STATS_OFFSET:]
+	battleStats[4] = byte(pokemon.Level)
+
+	stats := pokemon.BattleStat.Stats
+	binary.LittleEndian.PutUint16(battleStats[0x8:0xA], uint16(stats.Hp))
+	binary.LittleEndian.PutUint16(battleStats[0xA:0xC], uint16(stats.Attack))
+	binary.LittleEndian.PutUint16(battleStats[0xC:0xE], uint16(stats.Defense))
+	binary.LittleEndian.PutUint16(battleStats[0xE:0x10], uint16(stats.Speed))
+	binary.LittleEndian.PutUint16(battleStats[0x10:0x12], uint16(stats.SpAttack))
+	binary.LittleEndian.PutUint16(battleStats[0x12:0x14], uint16(stats.SpDefense))
+
+	// current HP can't exceed the new max HP
+	if binary.LittleEndian.Uint16(battleStats[0x6:0x8]) > uint16(stats.Hp) {
+		binary.LittleEndian.PutUint16(battleStats[0x6:0x8], uint16(stats.Hp))
+	}
+
+	if err := encodeName(pokemon.Name, blockC[:nicknameSize]); err != nil {
+		return err
+	}
+
+	return encodeName(pokemon.OTName, blockD[:otNameSize])
+}
@@ -0,0 +1,162 @@
+package rom_writer
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSetPokemon(t *testing.T) {
+	savefile, err := os.ReadFile("../rom_reader/mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon, err := rom_reader.GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon.HeldItemId = 217
+	pokemon.Friendship = 70
+	pokemon.Markings = rom_reader.MarkingHeart
+	pokemon.Contest.Sheen = 255
+	pokemon.Moves = [4]uint16{400, 420, 8, 14}
+	pokemon.MovePPUps[3] = 3
+	pokemon.MetDate = time.Date(2009, time.March, 22, 0, 0, 0, 0, time.UTC)
+	pokemon.PtMetLocation = 0
+	pokemon.MetLevel = 100
+	pokemon.PokeBall = 1
+	pokemon.IVs = rom_reader.Stats{Hp: 31, Attack: 31, Defense: 31, SpAttack: 31, SpDefense: 31, Speed: 31}
+	pokemon.EVs = rom_reader.Stats{Hp: 4, Attack: 252, Speed: 252}
+	pokemon.Level = 100
+	pokemon.Stats = rom_reader.Stats{
+		Hp: 281, Attack: 339, Defense: 166, SpAttack: 113, SpDefense: 206, Speed: 383,
+	}
+
+	if err := SetPokemon(savefile, 0, pokemon); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	actual, err := rom_reader.GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon.HeldItemName = "Quick Claw"
+	pokemon.MoveNames = [4]string{"Night Slash", "Ice Shard", "Ice Punch", "Swords Dance"}
+	if !cmp.Equal(actual, pokemon) {
+		t.Fatalf("expected %+v, but got %+v\n", pokemon, actual)
+	}
+}
+
+func TestSetPokemonOutOfRange(t *testing.T) {
+	savefile, err := os.ReadFile("../rom_reader/mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	err = SetPokemon(savefile, 1, rom_reader.Pokemon{})
+	if !errors.Is(err, rom_reader.ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestSetPokemonInvalidEV(t *testing.T) {
+	savefile, err := os.ReadFile("../rom_reader/mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon, err := rom_reader.GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon.EVs.Attack = 256
+	if err := SetPokemon(savefile, 0, pokemon); err == nil {
+		t.Fatal("Error not thrown for an EV that does not fit in a byte")
+	}
+}
+
+func TestSetPokemonInvalidStat(t *testing.T) {
+	savefile, err := os.ReadFile("../rom_reader/mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon, err := rom_reader.GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon.BattleStat.Stats.Speed = 0x10000
+	if err := SetPokemon(savefile, 0, pokemon); err == nil {
+		t.Fatal("Error not thrown for a stat that does not fit in 16 bits")
+	}
+}
+
+func TestSetPokemonPersonality(t *testing.T) {
+	savefile, err := os.ReadFile("../rom_reader/mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon, err := rom_reader.GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	// a different personality value changes the block order and the nature
+	pokemon.Personality = 0x00002005
+	if err := SetPokemon(savefile, 0, pokemon); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	actual, err := rom_reader.GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon.Nature = rom_reader.Natures[22]
+	if !cmp.Equal(actual, pokemon) {
+		t.Fatalf("expected %+v, but got %+v\n", pokemon, actual)
+	}
+}
+
+func TestSetPokemonName(t *testing.T) {
+	savefile, err := os.ReadFile("../rom_reader/mock_pokemon_data")
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon, err := rom_reader.GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	pokemon.Name = "Sneasel"
+	pokemon.IsNicknamed = true
+	pokemon.OTName = "Cynthia"
+	if err := SetPokemon(savefile, 0, pokemon); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	actual, err := rom_reader.GetPokemon(savefile, 0)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if !cmp.Equal(actual, pokemon) {
+		t.Fatalf("expected %+v, but got %+v\n", pokemon, actual)
+	}
+
+	pokemon.Name = "Way too long of a name"
+	if err := SetPokemon(savefile, 0, pokemon); err == nil {
+		t.Fatal("Error not thrown for a name that does not fit")
+	}
+}
@@ -0,0 +1,135 @@
+package save_container
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
+)
+
+/*
+Emulators and flash cart dumpers don't always export the raw 512 KiB flash image:
+- DeSmuME appends a 0x7A byte trailer (.dsv)
+- some dumpers pad the image to 1 MiB, often mirroring the first half
+- some tools trim trailing unused bytes
+*/
+
+// the DeSmuME trailer starts with DESMUME_SNIP and ends with DESMUME_MAGIC
+const DESMUME_TRAILER_SIZE = 0x7A
+const DESMUME_SNIP = "|<--Snip above here to create a raw sav by excluding this DeSmuME savedata footer:"
+const DESMUME_MAGIC = "|-DESMUME SAVE-|"
+
+const PADDED_SIZE uint = 1 << 20
+
+// value of erased flash, used to pad trimmed files
+const FILL_BYTE = 0xFF
+
+var ErrUnknownFormat = errors.New("unrecognised savefile container")
+var ErrInvalidSize = errors.New("flash image must be 512 KiB")
+
+type Format uint8
+
+const (
+	FormatRaw Format = iota
+	FormatDeSmuME
+	FormatPadded
+	FormatTrimmed
+)
+
+func (f Format) String() string {
+	switch f {
+	case FormatRaw:
+		return "raw"
+	case FormatDeSmuME:
+		return "DeSmuME"
+	case FormatPadded:
+		return "padded"
+	case FormatTrimmed:
+		return "trimmed"
+	default:
+		return "unknown"
+	}
+}
+
+// what is needed to put an edited flash image back into its original container
+type Container struct {
+	Format       Format
+	OriginalSize uint
+	suffix       []byte // bytes following the flash image, e.g. the DeSmuME trailer
+	mirrored     bool   // padded file whose second half repeats the image
+}
+
+// Recognises the container of `file` and returns a copy of the 512 KiB flash
+// image it holds, which can be passed to DetectGame and Parse
+func Unwrap(file []byte) ([]byte, Container, error) {
+	size := uint(len(file))
+	c := Container{OriginalSize: size}
+	image := make([]byte, game_profile.SAVEFILE_SIZE)
+
+	switch {
+	case size == game_profile.SAVEFILE_SIZE:
+		c.Format = FormatRaw
+	case isDeSmuME(file):
+		c.Format = FormatDeSmuME
+		c.suffix = bytes.Clone(file[game_profile.SAVEFILE_SIZE:])
+	case size == PADDED_SIZE:
+		c.Format = FormatPadded
+		c.mirrored = bytes.Equal(file[:game_profile.SAVEFILE_SIZE], file[game_profile.SAVEFILE_SIZE:])
+		if !c.mirrored {
+			c.suffix = bytes.Clone(file[game_profile.SAVEFILE_SIZE:])
+		}
+	case size > 0 && size < game_profile.SAVEFILE_SIZE:
+		c.Format = FormatTrimmed
+		for i := size; i < game_profile.SAVEFILE_SIZE; i++ {
+			image[i] = FILL_BYTE
+		}
+	default:
+		return nil, Container{}, ErrUnknownFormat
+	}
+
+	copy(image, file)
+	return image, c, nil
+}
+
+func isDeSmuME(file []byte) bool {
+	if uint(len(file)) < game_profile.SAVEFILE_SIZE+DESMUME_TRAILER_SIZE {
+		return false
+	}
+
+	trailer := file[len(file)-DESMUME_TRAILER_SIZE:]
+	return bytes.HasPrefix(trailer, []byte(DESMUME_SNIP)) && bytes.HasSuffix(trailer, []byte(DESMUME_MAGIC))
+}
+
+// Puts an edited flash image back into the container it was unwrapped from.
+// Trimmed files are trimmed back to their original size only if the edits
+// didn't touch the trimmed region; otherwise the full image is returned
+func (c Container) Wrap(image []byte) ([]byte, error) {
+	if uint(len(image)) != game_profile.SAVEFILE_SIZE {
+		return nil, ErrInvalidSize
+	}
+
+	res := bytes.Clone(image)
+
+	switch c.Format {
+	case FormatRaw:
+		return res, nil
+	case FormatDeSmuME:
+		return append(res, c.suffix...), nil
+	case FormatPadded:
+		if c.mirrored {
+			return append(res, image...), nil
+		}
+
+		return append(res, c.suffix...), nil
+	case FormatTrimmed:
+		for _, b := range image[c.OriginalSize:] {
+			if b != FILL_BYTE {
+				return res, nil
+			}
+		}
+
+		return res[:c.OriginalSize], nil
+	}
+
+	return nil, ErrUnknownFormat
+}
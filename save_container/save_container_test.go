@@ -0,0 +1,139 @@
+package save_container
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
+)
+
+const imageSize = game_profile.SAVEFILE_SIZE
+
+func mockImage() []byte {
+	image := make([]byte, imageSize)
+	for i := range image {
+		image[i] = byte(i)
+	}
+
+	return image
+}
+
+func desmumeTrailer() []byte {
+	trailer := []byte(DESMUME_SNIP)
+	trailer = append(trailer, make([]byte, DESMUME_TRAILER_SIZE-len(DESMUME_SNIP)-len(DESMUME_MAGIC))...)
+	return append(trailer, []byte(DESMUME_MAGIC)...)
+}
+
+func TestUnwrapRaw(t *testing.T) {
+	file := mockImage()
+
+	image, c, err := Unwrap(file)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if c.Format != FormatRaw || !bytes.Equal(image, file) {
+		t.Fatalf("expected raw image, got %s", c.Format)
+	}
+}
+
+func TestDeSmuMERoundTrip(t *testing.T) {
+	trailer := desmumeTrailer()
+	if len(trailer) != DESMUME_TRAILER_SIZE {
+		t.Fatalf("expected trailer of 0x%x bytes, got 0x%x", DESMUME_TRAILER_SIZE, len(trailer))
+	}
+
+	file := append(mockImage(), trailer...)
+
+	image, c, err := Unwrap(file)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if c.Format != FormatDeSmuME || !bytes.Equal(image, file[:imageSize]) {
+		t.Fatalf("expected DeSmuME image, got %s", c.Format)
+	}
+
+	image[0] = 0x42
+	wrapped, err := c.Wrap(image)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if len(wrapped) != len(file) || wrapped[0] != 0x42 || !bytes.HasSuffix(wrapped, trailer) {
+		t.Fatal("DeSmuME trailer not restored")
+	}
+}
+
+func TestPaddedMirrored(t *testing.T) {
+	file := append(mockImage(), mockImage()...)
+
+	image, c, err := Unwrap(file)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if c.Format != FormatPadded {
+		t.Fatalf("expected padded image, got %s", c.Format)
+	}
+
+	image[0] = 0x42
+	wrapped, _ := c.Wrap(image)
+	if uint(len(wrapped)) != PADDED_SIZE || wrapped[0] != 0x42 || wrapped[imageSize] != 0x42 {
+		t.Fatal("edit not mirrored into the second half")
+	}
+}
+
+func TestPaddedBlank(t *testing.T) {
+	file := append(mockImage(), bytes.Repeat([]byte{FILL_BYTE}, int(imageSize))...)
+
+	image, c, _ := Unwrap(file)
+	image[0] = 0x42
+	wrapped, _ := c.Wrap(image)
+
+	if wrapped[0] != 0x42 || wrapped[imageSize] != FILL_BYTE {
+		t.Fatal("padding not preserved")
+	}
+}
+
+func TestTrimmed(t *testing.T) {
+	file := mockImage()[:0x50000]
+
+	image, c, err := Unwrap(file)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if c.Format != FormatTrimmed || uint(len(image)) != imageSize || image[0x50000] != FILL_BYTE {
+		t.Fatalf("expected trimmed image padded with 0x%x, got %s", FILL_BYTE, c.Format)
+	}
+
+	wrapped, _ := c.Wrap(image)
+	if !bytes.Equal(wrapped, file) {
+		t.Fatal("trimmed file not restored to its original size")
+	}
+
+	// edits past the original end keep the full image
+	image[0x60000] = 0x42
+	wrapped, _ = c.Wrap(image)
+	if uint(len(wrapped)) != imageSize {
+		t.Fatalf("expected full image, got 0x%x bytes", len(wrapped))
+	}
+}
+
+func TestUnwrapUnknown(t *testing.T) {
+	if _, _, err := Unwrap(make([]byte, imageSize+1)); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatal("Error not thrown for unknown container")
+	}
+
+	if _, _, err := Unwrap(nil); !errors.Is(err, ErrUnknownFormat) {
+		t.Fatal("Error not thrown for empty file")
+	}
+}
+
+func TestWrapInvalidSize(t *testing.T) {
+	if _, err := (Container{}).Wrap(make([]byte, 1024)); !errors.Is(err, ErrInvalidSize) {
+		t.Fatal("Error not thrown for invalid image size")
+	}
+}
@@ -0,0 +1,96 @@
+package stat_calculator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/data"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+var ErrUnknownSpecies = errors.New("unknown species")
+var ErrNoBattleStats = errors.New("pokemon has no battle stats")
+
+// a stat whose stored value differs from the one the game would compute
+type Discrepancy struct {
+	Stat     rom_reader.Stat
+	Expected uint
+	Actual   uint
+}
+
+func (d Discrepancy) String() string {
+	return fmt.Sprintf("%s: expected %d, got %d", d.Stat, d.Expected, d.Actual)
+}
+
+// Computes a single stat the way the game does. `base` is the species'
+// base stat for `stat`, and the nature multiplier is applied last
+func CalculateStat(stat rom_reader.Stat, base, iv, ev, level uint, nature rom_reader.Nature) uint {
+	raw := (2*base + iv + ev/4) * level / 100
+
+	if stat == rom_reader.StatHp {
+		// shedinja is the only species with a base HP of 1, and always has 1 HP
+		if base == 1 {
+			return 1
+		}
+		return raw + level + 10
+	}
+
+	return (raw + 5) * nature.Multiplier(stat) / 10
+}
+
+func CalculateStats(base data.BaseStats, ivs, evs rom_reader.Stats, level uint, nature rom_reader.Nature) rom_reader.Stats {
+	calc := func(stat rom_reader.Stat, b uint8) uint {
+		return CalculateStat(stat, uint(b), ivs.Get(stat), evs.Get(stat), level, nature)
+	}
+
+	return rom_reader.Stats{
+		Hp:        calc(rom_reader.StatHp, base.Hp),
+		Attack:    calc(rom_reader.StatAttack, base.Attack),
+		Defense:   calc(rom_reader.StatDefense, base.Defense),
+		SpAttack:  calc(rom_reader.StatSpAttack, base.SpAttack),
+		SpDefense: calc(rom_reader.StatSpDefense, base.SpDefense),
+		Speed:     calc(rom_reader.StatSpeed, base.Speed),
+	}
+}
+
+// lists the stats that differ between `expected` and `actual`, in HP, Atk,
+// Def, SpA, SpD, Spe order. Returns nil when they all match
+func Compare(expected, actual rom_reader.Stats) []Discrepancy {
+	var res []Discrepancy
+
+	for _, stat := range rom_reader.AllStats {
+		if e, a := expected.Get(stat), actual.Get(stat); e != a {
+			res = append(res, Discrepancy{stat, e, a})
+		}
+	}
+
+	return res
+}
+
+// Expected battle stats of a pokemon, computed from its species, form,
+// IVs, EVs, level and nature
+func ExpectedStats(p rom_reader.Pokemon) (rom_reader.Stats, error) {
+	base, ok := data.BaseStatsOf(p.PokedexId, p.Form)
+	if !ok {
+		return rom_reader.Stats{}, ErrUnknownSpecies
+	}
+
+	return CalculateStats(base, p.IVs, p.EVs, p.Level, p.Nature), nil
+}
+
+// Compares the decrypted battle stats of a party pokemon with the ones the
+// game would compute. A discrepancy means either the save is corrupt or the
+// pokemon was edited without recalculating its stats.
+// Box pokemon have no battle stats and return ErrNoBattleStats
+func CheckBattleStats(p rom_reader.Pokemon) ([]Discrepancy, error) {
+	if p.Level == 0 {
+		return nil, ErrNoBattleStats
+	}
+
+	expected, err := ExpectedStats(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return Compare(expected, p.Stats), nil
+}
@@ -0,0 +1,78 @@
+package stat_calculator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/data"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/internal/test_fixtures"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCalculateStats(t *testing.T) {
+	// level 100 adamant garchomp with 31 IVs and 252 Atk / 252 Spe / 4 HP
+	base, _ := data.BaseStatsOf(445, 0)
+	adamant, _ := rom_reader.NatureByName("Adamant")
+	ivs := rom_reader.Stats{Hp: 31, Attack: 31, Defense: 31, SpAttack: 31, SpDefense: 31, Speed: 31}
+	evs := rom_reader.Stats{Hp: 4, Attack: 252, Speed: 252}
+
+	actual := CalculateStats(base, ivs, evs, 100, adamant)
+	expected := rom_reader.Stats{Hp: 358, Attack: 394, Defense: 226, SpAttack: 176, SpDefense: 206, Speed: 303}
+
+	if !cmp.Equal(actual, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, actual)
+	}
+}
+
+func TestShedinjaHp(t *testing.T) {
+	base, _ := data.BaseStatsOf(292, 0)
+	hp := CalculateStat(rom_reader.StatHp, uint(base.Hp), 31, 252, 100, rom_reader.Natures[0])
+	if hp != 1 {
+		t.Fatalf("expected shedinja to have 1 HP, got %d", hp)
+	}
+}
+
+func TestCheckBattleStats(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+
+	discrepancies, err := CheckBattleStats(weavile)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if len(discrepancies) != 0 {
+		t.Fatalf("expected no discrepancies, got %v", discrepancies)
+	}
+}
+
+func TestCheckBattleStatsEdited(t *testing.T) {
+	weavile := test_fixtures.Weavile(t)
+	weavile.IVs.Speed = 31
+	weavile.Stats.Hp = 1
+
+	discrepancies, err := CheckBattleStats(weavile)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	expected := []Discrepancy{
+		{rom_reader.StatHp, 163, 1},
+		{rom_reader.StatSpeed, 224, 215},
+	}
+
+	if !cmp.Equal(discrepancies, expected) {
+		t.Fatalf("expected %v, got %v", expected, discrepancies)
+	}
+}
+
+func TestCheckBattleStatsErrors(t *testing.T) {
+	if _, err := CheckBattleStats(rom_reader.Pokemon{PokedexId: 461}); !errors.Is(err, ErrNoBattleStats) {
+		t.Fatalf("expected ErrNoBattleStats, got %v", err)
+	}
+
+	unknown := rom_reader.Pokemon{PokedexId: 600, BattleStat: rom_reader.BattleStat{Level: 50}}
+	if _, err := CheckBattleStats(unknown); !errors.Is(err, ErrUnknownSpecies) {
+		t.Fatalf("expected ErrUnknownSpecies, got %v", err)
+	}
+}
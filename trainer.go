@@ -0,0 +1,101 @@
+package parser
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"time"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/char_encoder"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+// offsets below are relative to the trainer section of the small block
+const TRAINER_NAME_OFFSET = 0x0
+const TRAINER_NAME_SIZE = 16
+const TRAINER_ID_OFFSET = 0x10
+const SECRET_ID_OFFSET = 0x12
+const MONEY_OFFSET = 0x14
+const GENDER_OFFSET = 0x18
+const LANGUAGE_OFFSET = 0x19
+const BADGES_OFFSET = 0x1A
+
+// follows the u16 coin count at 0x20. Hours are a u16, followed by a byte
+// each for minutes and seconds
+const PLAY_TIME_OFFSET = 0x22
+
+const POKEDEX_OWNED_SIZE = 0x40
+
+// bitfield of earned gym badges, in the order they are awarded
+type Badges uint8
+
+// names of the earned badges, given the badge names of the game in award order
+func (b Badges) Names(badgeNames [8]string) []string {
+	var res []string
+
+	for i, name := range badgeNames {
+		if b&(1<<i) != 0 {
+			res = append(res, name)
+		}
+	}
+
+	return res
+}
+
+func (b Badges) Count() uint {
+	return uint(bits.OnesCount8(uint8(b)))
+}
+
+type Trainer struct {
+	Name           string
+	TrainerId      uint16
+	SecretId       uint16
+	Gender         rom_reader.Gender // Male or Female
+	Language       uint8
+	Money          uint32
+	Badges         Badges
+	BadgeNames     []string
+	PlayTime       time.Duration
+	AdventureStart time.Time
+	PokedexOwned   uint // number of species flagged as owned in the pokedex
+}
+
+// in-game timestamps count seconds from the start of the year 2000
+var epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// `smallBlock` must be a slice starting at the small block
+func getTrainer(smallBlock []byte, profile game_profile.GameProfile) Trainer {
+	trainer := smallBlock[profile.TrainerOffset:]
+	hours := binary.LittleEndian.Uint16(trainer[PLAY_TIME_OFFSET : PLAY_TIME_OFFSET+2])
+	minutes := trainer[PLAY_TIME_OFFSET+2]
+	seconds := trainer[PLAY_TIME_OFFSET+3]
+	playTime := time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second
+
+	adventureStartOffset := profile.AdventureStartOffset
+	adventureStart := binary.LittleEndian.Uint32(smallBlock[adventureStartOffset : adventureStartOffset+4])
+
+	owned := uint(0)
+	ownedOffset := profile.PokedexOffset + 4
+	ownedFlags := smallBlock[ownedOffset : ownedOffset+POKEDEX_OWNED_SIZE]
+	for _, b := range ownedFlags {
+		owned += uint(bits.OnesCount8(b))
+	}
+
+	badges := Badges(trainer[BADGES_OFFSET])
+
+	return Trainer{
+		char_encoder.DecodeBytes(trainer[TRAINER_NAME_OFFSET : TRAINER_NAME_OFFSET+TRAINER_NAME_SIZE]),
+		binary.LittleEndian.Uint16(trainer[TRAINER_ID_OFFSET : TRAINER_ID_OFFSET+2]),
+		binary.LittleEndian.Uint16(trainer[SECRET_ID_OFFSET : SECRET_ID_OFFSET+2]),
+		rom_reader.Gender(trainer[GENDER_OFFSET]),
+		trainer[LANGUAGE_OFFSET],
+		binary.LittleEndian.Uint32(trainer[MONEY_OFFSET : MONEY_OFFSET+4]),
+		badges,
+		badges.Names(profile.BadgeNames),
+		playTime,
+		epoch.Add(time.Duration(adventureStart) * time.Second),
+		owned,
+	}
+}
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetTrainer(t *testing.T) {
+	profile := game_profile.Platinum
+	smallBlock := make([]byte, profile.SmallBlockFooterOffset())
+	trainer := smallBlock[profile.TrainerOffset:]
+
+	// "DING" followed by a terminator
+	name := []uint16{0x012E, 0x0133, 0x0138, 0x0131, 0xFFFF}
+	for i, c := range name {
+		binary.LittleEndian.PutUint16(trainer[TRAINER_NAME_OFFSET+2*i:], c)
+	}
+
+	binary.LittleEndian.PutUint16(trainer[TRAINER_ID_OFFSET:], 12345)
+	binary.LittleEndian.PutUint16(trainer[SECRET_ID_OFFSET:], 54321)
+	binary.LittleEndian.PutUint32(trainer[MONEY_OFFSET:], 999999)
+	trainer[GENDER_OFFSET] = 1
+	trainer[LANGUAGE_OFFSET] = 2
+	trainer[BADGES_OFFSET] = 0b00000111
+	// 1337 coins, then 123h 45m 06s of play time, written at their known offsets
+	// rather than through the constants under test
+	copy(trainer[0x20:], []byte{0x39, 0x05, 0x7B, 0x00, 0x2D, 0x06})
+	binary.LittleEndian.PutUint32(smallBlock[profile.AdventureStartOffset:], 86400)
+	smallBlock[profile.PokedexOffset+4] = 0xFF
+	smallBlock[profile.PokedexOffset+5] = 0x01
+
+	expected := Trainer{
+		"DING",
+		12345,
+		54321,
+		rom_reader.Female,
+		2,
+		999999,
+		Badges(0b00000111),
+		[]string{"Coal", "Forest", "Cobble"},
+		123*time.Hour + 45*time.Minute + 6*time.Second,
+		time.Date(2000, time.January, 2, 0, 0, 0, 0, time.UTC),
+		9,
+	}
+
+	actual := getTrainer(smallBlock, profile)
+	if !cmp.Equal(actual, expected) {
+		t.Fatalf("expected %+v, but got %+v\n", expected, actual)
+	}
+}
+
+func TestBadgeNames(t *testing.T) {
+	badges := Badges(0b10000101)
+	expected := []string{"Coal", "Cobble", "Beacon"}
+	names := badges.Names(game_profile.Platinum.BadgeNames)
+
+	if !cmp.Equal(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+
+	expected = []string{"Zephyr", "Plain", "Rising"}
+	names = badges.Names(game_profile.HeartGoldSoulSilver.BadgeNames)
+
+	if !cmp.Equal(names, expected) {
+		t.Fatalf("expected %v, got %v", expected, names)
+	}
+
+	if badges.Count() != 3 {
+		t.Fatalf("expected 3 badges, got %d", badges.Count())
+	}
+}
@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
+)
+
+var ErrUnrepairable = errors.New("both copies of a block are corrupt")
+
+// describes a block that was restored from the other chunk
+type RepairAction struct {
+	BlockOffset  uint   // start of the block that was overwritten
+	SourceOffset uint   // start of the valid copy it was restored from
+	SaveNumber   uint32 // save number the restored block now carries
+	OldChecksum  uint16 // checksum stored in the corrupt block's footer
+	NewChecksum  uint16
+}
+
+func (a RepairAction) String() string {
+	return fmt.Sprintf(
+		"restored block at 0x%05x from 0x%05x (saveNumber=%d, checksum 0x%04x -> 0x%04x)",
+		a.BlockOffset, a.SourceOffset, a.SaveNumber, a.OldChecksum, a.NewChecksum,
+	)
+}
+
+// the block's data followed by its footer
+func (b block) raw(savefile []byte) []byte {
+	return savefile[b.offset : b.offset+uint(len(b.blockData))+b.footerSize]
+}
+
+// picks the valid copy of a block when the other copy is corrupt.
+// returns nil if both copies are valid
+func restorePair(first, second block) (*block, *block, error) {
+	firstErr := first.validate()
+	secondErr := second.validate()
+
+	switch {
+	case firstErr == nil && secondErr == nil:
+		return nil, nil, nil
+	case firstErr == nil:
+		return &first, &second, nil
+	case secondErr == nil:
+		return &second, &first, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: %w", ErrUnrepairable, errors.Join(firstErr, secondErr))
+}
+
+// Restores every corrupt small or big block from its valid counterpart in the
+// other chunk, so the savefile loads again. The restored block is an exact copy,
+// footer included, so both chunks carry the same save number and the game loads
+// the same data whichever one it picks. Progress saved only in the corrupt block
+// is lost.
+// The savefile is left untouched if a block cannot be repaired
+func Repair(savefile []byte, profile game_profile.GameProfile) ([]RepairAction, error) {
+	if uint(len(savefile)) != game_profile.SAVEFILE_SIZE {
+		return nil, ErrInvalidSize
+	}
+
+	first := getChunk(savefile, profile, 0)
+	second := getChunk(savefile, profile, profile.SecondChunkOffset)
+
+	type restore struct{ src, dst *block }
+	var restores []restore
+
+	for _, pair := range [][2]block{
+		{first.smallBlock, second.smallBlock},
+		{first.bigBlock, second.bigBlock},
+	} {
+		src, dst, err := restorePair(pair[0], pair[1])
+		if err != nil {
+			return nil, err
+		}
+
+		if src != nil {
+			restores = append(restores, restore{src, dst})
+		}
+	}
+
+	var actions []RepairAction
+
+	for _, r := range restores {
+		copy(r.dst.raw(savefile), r.src.raw(savefile))
+		r.dst.updateChecksum(savefile)
+
+		actions = append(actions, RepairAction{
+			r.dst.offset,
+			r.src.offset,
+			r.src.saveNumber(),
+			r.dst.checksum(),
+			r.src.checksum(),
+		})
+	}
+
+	return actions, nil
+}
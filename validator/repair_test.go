@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestRepair(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	savefile[0x20] = 0x42
+	writeChunk(savefile, 0, 1)
+	writeChunk(savefile, secondChunkOffset, 2)
+
+	// corrupt the newest small block
+	savefile[secondChunkOffset+0x10] ^= 0xFF
+
+	actions, err := Repair(savefile, platinum)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if len(actions) != 1 {
+		t.Fatalf("expected 1 repair, got %v", actions)
+	}
+
+	if actions[0].BlockOffset != secondChunkOffset || actions[0].SourceOffset != 0 || actions[0].SaveNumber != 1 {
+		t.Fatalf("unexpected repair %s", actions[0])
+	}
+
+	if !Validate(savefile, platinum) {
+		t.Fatal("savefile still invalid after repair")
+	}
+
+	first := getChunk(savefile, platinum, 0)
+	second := getChunk(savefile, platinum, secondChunkOffset)
+	if !bytes.Equal(first.smallBlock.raw(savefile), second.smallBlock.raw(savefile)) {
+		t.Fatal("small block was not restored from the first chunk")
+	}
+
+	// the intact big block keeps its own save number
+	if second.bigBlock.saveNumber() != 2 {
+		t.Fatalf("expected big block save number 2, got %d", second.bigBlock.saveNumber())
+	}
+}
+
+func TestRepairValid(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	writeChunk(savefile, 0, 1)
+	writeChunk(savefile, secondChunkOffset, 2)
+
+	actions, err := Repair(savefile, platinum)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if len(actions) != 0 {
+		t.Fatalf("expected no repairs, got %v", actions)
+	}
+}
+
+func TestRepairUnrepairable(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	writeChunk(savefile, 0, 1)
+	writeChunk(savefile, secondChunkOffset, 2)
+
+	// the first big block can be restored, but neither small block can
+	savefile[platinum.BigBlockOffset] ^= 0xFF
+	savefile[0x10] ^= 0xFF
+	savefile[secondChunkOffset+0x10] ^= 0xFF
+
+	original := bytes.Clone(savefile)
+
+	if _, err := Repair(savefile, platinum); !errors.Is(err, ErrUnrepairable) {
+		t.Fatalf("expected ErrUnrepairable, got %v", err)
+	}
+
+	if !bytes.Equal(original, savefile) {
+		t.Fatal("savefile modified by a failed repair")
+	}
+}
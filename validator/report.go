@@ -0,0 +1,189 @@
+package validator
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+type BlockReport struct {
+	Offset            uint
+	Footer            Footer
+	ComputedChecksum  uint16
+	ChecksumValid     bool
+	ExpectedBlockSize uint
+	BlockSizeValid    bool // whether the block size in the footer matches the layout
+}
+
+type ChunkReport struct {
+	Offset     uint
+	SmallBlock BlockReport
+	BigBlock   BlockReport
+}
+
+type PokemonReport struct {
+	Slot             uint
+	Empty            bool
+	StoredChecksum   uint16
+	ComputedChecksum uint16
+	ChecksumValid    bool
+}
+
+type ValidationReport struct {
+	Profile   game_profile.GameProfile
+	SizeValid bool
+	Chunks    []ChunkReport
+	Newest    SaveBlocks // blocks with the highest save numbers
+	Used      SaveBlocks // blocks Parse reads from
+	Party     []PokemonReport
+}
+
+func newBlockReport(b block, expectedSize uint) BlockReport {
+	computed := crc16_ccitt(b.blockData)
+
+	return BlockReport{
+		b.offset,
+		b.footer,
+		computed,
+		computed == b.footer.Checksum,
+		expectedSize,
+		uint(b.footer.BlockSize) == expectedSize,
+	}
+}
+
+func (c ChunkReport) Valid() bool {
+	return c.SmallBlock.ChecksumValid && c.BigBlock.ChecksumValid
+}
+
+// checks the data checksum of every party pokemon in the chunk at `chunkOffset`
+func partyReport(savefile []byte, profile game_profile.GameProfile, chunkOffset uint) []PokemonReport {
+	var res []PokemonReport
+	party := savefile[chunkOffset+profile.PartyOffset:]
+
+	for i := uint(0); i < rom_reader.PARTY_SIZE; i++ {
+		slot := party[i*rom_reader.PARTY_POKEMON_SIZE : (i+1)*rom_reader.PARTY_POKEMON_SIZE]
+		stored := binary.LittleEndian.Uint16(slot[6:8])
+		report := PokemonReport{i, false, stored, stored, true}
+
+		pokemon, err := rom_reader.GetPokemon(slot, 0)
+		var checksumErr *rom_reader.ChecksumError
+
+		if errors.As(err, &checksumErr) {
+			report.ComputedChecksum = checksumErr.Actual
+			report.ChecksumValid = false
+		} else if err == nil && pokemon.IsEmpty() {
+			report.Empty = true
+		}
+
+		res = append(res, report)
+	}
+
+	return res
+}
+
+// Builds a detailed report of every check performed while validating the
+// savefile, for diagnosing why a savefile is rejected
+func Report(savefile []byte, profile game_profile.GameProfile) ValidationReport {
+	res := ValidationReport{Profile: profile}
+	if uint(len(savefile)) != game_profile.SAVEFILE_SIZE {
+		return res
+	}
+	res.SizeValid = true
+
+	first := getChunk(savefile, profile, 0)
+	second := getChunk(savefile, profile, profile.SecondChunkOffset)
+
+	for _, c := range []chunk{first, second} {
+		res.Chunks = append(res.Chunks, ChunkReport{
+			c.offset,
+			newBlockReport(c.smallBlock, profile.SmallBlockSize),
+			newBlockReport(c.bigBlock, profile.BigBlockSize),
+		})
+	}
+
+	newestSmall, _ := byAge(first, second, smallBlockOf)
+	newestBig, _ := byAge(first, second, bigBlockOf)
+	res.Newest = SaveBlocks{newestSmall.offset, newestBig.offset}
+
+	// fall back to the newest blocks so the party is still reported
+	used, err := MostRecentBlocks(savefile, profile)
+	if err != nil {
+		used = res.Newest
+	}
+	res.Used = used
+	res.Party = partyReport(savefile, profile, used.SmallChunk)
+
+	return res
+}
+
+// true if the blocks Parse reads from and every party pokemon pass their checksums
+func (r ValidationReport) Valid() bool {
+	if !r.SizeValid {
+		return false
+	}
+
+	smallValid, bigValid := false, false
+	for _, c := range r.Chunks {
+		if c.Offset == r.Used.SmallChunk && c.SmallBlock.ChecksumValid {
+			smallValid = true
+		}
+		if c.Offset == r.Used.BigChunk && c.BigBlock.ChecksumValid {
+			bigValid = true
+		}
+	}
+
+	for _, p := range r.Party {
+		if !p.ChecksumValid {
+			return false
+		}
+	}
+
+	return smallValid && bigValid
+}
+
+func (b BlockReport) String() string {
+	return fmt.Sprintf(
+		"block 0x%05x: identifier=0x%x saveNumber=%d blockSize=0x%x (expected 0x%x, ok=%t) K=0x%x T=0x%x checksum stored=0x%04x computed=0x%04x ok=%t",
+		b.Offset, b.Footer.Identifier, b.Footer.SaveNumber, b.Footer.BlockSize,
+		b.ExpectedBlockSize, b.BlockSizeValid, b.Footer.K, b.Footer.T,
+		b.Footer.Checksum, b.ComputedChecksum, b.ChecksumValid,
+	)
+}
+
+// validation report format specifier
+func (r ValidationReport) String() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "%s savefile, size ok=%t\n", r.Profile, r.SizeValid)
+	for _, c := range r.Chunks {
+		fmt.Fprintf(&sb, "chunk 0x%05x (valid=%t):\n", c.Offset, c.Valid())
+		fmt.Fprintf(&sb, "\tsmall %s\n", c.SmallBlock)
+		fmt.Fprintf(&sb, "\tbig   %s\n", c.BigBlock)
+	}
+
+	fmt.Fprintf(
+		&sb, "newest small block: chunk 0x%05x, big block: chunk 0x%05x\n",
+		r.Newest.SmallChunk, r.Newest.BigChunk,
+	)
+	fmt.Fprintf(
+		&sb, "used small block: chunk 0x%05x, big block: chunk 0x%05x\n",
+		r.Used.SmallChunk, r.Used.BigChunk,
+	)
+	for _, p := range r.Party {
+		if p.Empty {
+			fmt.Fprintf(&sb, "party slot %d: empty\n", p.Slot)
+			continue
+		}
+
+		fmt.Fprintf(
+			&sb, "party slot %d: checksum stored=0x%04x computed=0x%04x ok=%t\n",
+			p.Slot, p.StoredChecksum, p.ComputedChecksum, p.ChecksumValid,
+		)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+func TestReport(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	writeChunk(savefile, 0, 1)
+	writeChunk(savefile, secondChunkOffset, 2)
+
+	// corrupt the newest small block
+	savefile[secondChunkOffset+0x10] ^= 0xFF
+
+	report := Report(savefile, platinum)
+	if !report.Valid() {
+		t.Fatalf("expected a valid report, got:\n%s", report)
+	}
+
+	if report.Newest != (SaveBlocks{secondChunkOffset, secondChunkOffset}) {
+		t.Fatalf("expected newest blocks in chunk 0x%x, got %+v", secondChunkOffset, report.Newest)
+	}
+
+	// only the small block falls back to the older chunk
+	if report.Used != (SaveBlocks{0, secondChunkOffset}) {
+		t.Fatalf("expected the small block from 0x0 and big block from 0x%x, got %+v", secondChunkOffset, report.Used)
+	}
+
+	second := report.Chunks[1]
+	if second.Valid() || second.SmallBlock.ChecksumValid || !second.BigBlock.ChecksumValid {
+		t.Fatalf("expected only the second small block to be invalid, got:\n%s", report)
+	}
+
+	if second.SmallBlock.Footer.SaveNumber != 2 || !second.SmallBlock.BlockSizeValid {
+		t.Fatalf("unexpected footer %+v", second.SmallBlock.Footer)
+	}
+
+	if len(report.Party) != rom_reader.PARTY_SIZE {
+		t.Fatalf("expected %d party slots, got %d", rom_reader.PARTY_SIZE, len(report.Party))
+	}
+
+	for _, p := range report.Party {
+		if !p.Empty || !p.ChecksumValid {
+			t.Fatalf("expected empty party slot, got %+v", p)
+		}
+	}
+}
+
+func TestReportPartyChecksum(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+
+	// non-zero data in the third party slot without a matching checksum
+	slot := platinum.PartyOffset + 2*rom_reader.PARTY_POKEMON_SIZE
+	savefile[slot] = 0x01
+	savefile[slot+0x10] = 0x01
+	writeChunk(savefile, 0, 1)
+
+	report := Report(savefile, platinum)
+	if report.Valid() {
+		t.Fatalf("expected an invalid report, got:\n%s", report)
+	}
+
+	p := report.Party[2]
+	if p.Empty || p.ChecksumValid || p.StoredChecksum == p.ComputedChecksum {
+		t.Fatalf("expected a checksum mismatch, got %+v", p)
+	}
+
+	if !strings.Contains(report.String(), "party slot 2: checksum") {
+		t.Fatalf("party slot missing from report:\n%s", report)
+	}
+}
+
+func TestReportInvalidSize(t *testing.T) {
+	report := Report(make([]byte, 1024), platinum)
+	if report.SizeValid || report.Valid() {
+		t.Fatal("expected an invalid report for invalid savefile size")
+	}
+}
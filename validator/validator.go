@@ -2,7 +2,10 @@ package validator
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
 )
 
 /*
@@ -55,41 +58,66 @@ var seeds = []uint{
 
 // a "chunk" denotes a pair of small + big block that are adjacent in memory
 type chunk struct {
+	offset     uint
 	smallBlock block
-	bigBlock block
+	bigBlock   block
 }
 
 type block struct {
-	blockData []byte
-	footer footer
+	offset     uint
+	blockData  []byte
+	footer     Footer
+	footerSize uint
+}
+
+// stored at the end of every block
+type Footer struct {
+	Identifier uint32
+	SaveNumber uint32
+	BlockSize  uint32
+	K          uint32
+	T          uint16
+	Checksum   uint16
+}
+
+var ErrInvalidSize = errors.New("invalid savefile size")
+
+// returned when a block's CRC16 does not match the checksum in its footer
+type ChecksumError struct {
+	Offset   uint // start of the corrupt block in the savefile
+	Stored   uint16
+	Computed uint16
 }
 
-type footer struct {
-	identifier uint32
-	saveNumber	uint32
-	blockSize uint32
-	K	uint32
-	T  uint16
-	checksum uint16
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf(
+		"block at 0x%x is corrupt: stored checksum 0x%x, computed 0x%x",
+		e.Offset, e.Stored, e.Computed,
+	)
 }
 
-const savefileSize int = 1 << 19
-const footerSize uint = 0x14
-const secondChunkOffset uint = 0x40000
+// fields are read from the end of the footer, since HGSS footers
+// are 0x10 bytes long and have no identifier
+func getFooter(buf []byte) Footer {
+	end := len(buf)
+	f := Footer{
+		0,
+		binary.LittleEndian.Uint32(buf[end-0x10 : end-0xC]),
+		binary.LittleEndian.Uint32(buf[end-0xC : end-0x8]),
+		binary.LittleEndian.Uint32(buf[end-0x8 : end-0x4]),
+		binary.LittleEndian.Uint16(buf[end-0x4 : end-0x2]),
+		binary.LittleEndian.Uint16(buf[end-0x2 : end]),
+	}
 
-func getFooter(buf []byte) footer {
-	return footer{
-		binary.LittleEndian.Uint32(buf[:0x4]),
-		binary.LittleEndian.Uint32(buf[0x4 : 0x8]),
-		binary.LittleEndian.Uint32(buf[0x8 : 0xC]),
-		binary.LittleEndian.Uint32(buf[0xC : 0x10]),
-		binary.LittleEndian.Uint16(buf[0x10 : 0x12]),
-		binary.LittleEndian.Uint16(buf[0x12 : 0x14]),
+	if end >= 0x14 {
+		f.Identifier = binary.LittleEndian.Uint32(buf[end-0x14 : end-0x10])
 	}
+
+	return f
 }
 
 // footer format specifier
-func (f footer) String() string {
+func (f Footer) String() string {
 	return fmt.Sprintf(`
 	footer {
 		identifier = 0x%x,
@@ -98,7 +126,7 @@ func (f footer) String() string {
 		K = 0x%x,
 		T = 0x%x,
 		checksum = 0x%x,
-	}`, f.identifier, f.saveNumber, f.blockSize, f.K, f.T, f.checksum)
+	}`, f.Identifier, f.SaveNumber, f.BlockSize, f.K, f.T, f.Checksum)
 }
 
 // Computes a checksum via the CRC16-CCITT algorithm on the given data
@@ -106,58 +134,209 @@ func crc16_ccitt(data []byte) uint16 {
 	sum := uint(0xFFFF)
 
 	for _, b := range data {
-		sum = (sum << 8) ^ seeds[b ^ byte((sum >> 8))]
+		sum = (sum << 8) ^ seeds[b^byte((sum>>8))]
 	}
 
 	return uint16(sum)
 }
 
-func (c chunk) isValid() bool {
-	smallChecksum := crc16_ccitt(c.smallBlock.blockData)
-	if smallChecksum != c.smallBlock.footer.checksum {
-		return false
+func (b block) saveNumber() uint32 { return b.footer.SaveNumber }
+func (b block) checksum() uint16   { return b.footer.Checksum }
+
+func (b block) validate() error {
+	checksum := crc16_ccitt(b.blockData)
+	if checksum != b.footer.Checksum {
+		return &ChecksumError{b.offset, b.footer.Checksum, checksum}
+	}
+
+	return nil
+}
+
+func (c chunk) validate() error {
+	if err := c.smallBlock.validate(); err != nil {
+		return err
 	}
 
-	bigChecksum := crc16_ccitt(c.bigBlock.blockData)
-	return bigChecksum == c.bigBlock.footer.checksum
+	return c.bigBlock.validate()
+}
+
+func (c chunk) isValid() bool {
+	return c.validate() == nil
 }
 
-func getChunk(savefile []byte, offset uint) chunk {
-	smallBlockFooterAddr := uint(0x0CF18) + offset
-	bigBlockFooterAddr := uint(0x1F0FC) + offset
-	bigBlockStart := uint(0xCF2C) + offset
+func getChunk(savefile []byte, profile game_profile.GameProfile, offset uint) chunk {
+	footerSize := profile.FooterSize
+	smallBlockFooterAddr := profile.SmallBlockFooterOffset() + offset
+	bigBlockFooterAddr := profile.BigBlockFooterOffset() + offset
+	bigBlockStart := profile.BigBlockOffset + offset
 
 	smallBlock := block{
-		savefile[offset : smallBlockFooterAddr],	
+		offset,
+		savefile[offset:smallBlockFooterAddr],
 		getFooter(savefile[smallBlockFooterAddr : smallBlockFooterAddr+footerSize]),
+		footerSize,
 	}
 
 	bigBlock := block{
-		savefile[bigBlockStart : bigBlockFooterAddr],
+		bigBlockStart,
+		savefile[bigBlockStart:bigBlockFooterAddr],
 		getFooter(savefile[bigBlockFooterAddr : bigBlockFooterAddr+footerSize]),
+		footerSize,
 	}
 
-	return chunk{ smallBlock, bigBlock }
+	return chunk{offset, smallBlock, bigBlock}
 }
 
-// validates the given .sav file
-func Validate(savefile []byte) bool {
-	if len(savefile) != savefileSize {
-		return false
+// chunk offsets of the small and big block a save is read from. The big block
+// is only rewritten when the PC boxes change, so the newest big block may sit
+// in a different chunk than the newest small block
+type SaveBlocks struct {
+	SmallChunk uint
+	BigChunk   uint
+}
+
+func smallBlockOf(c chunk) block { return c.smallBlock }
+func bigBlockOf(c chunk) block   { return c.bigBlock }
+
+// orders the two copies of a block by their own save number, newest first
+func byAge(first, second chunk, blockOf func(chunk) block) (chunk, chunk) {
+	if blockOf(second).footer.SaveNumber > blockOf(first).footer.SaveNumber {
+		return second, first
 	}
+	return first, second
+}
 
-	firstChunk := getChunk(savefile, 0)
-	secondChunk := getChunk(savefile, secondChunkOffset)
+// Returns the offset of the chunk holding the most recent valid copy of a block.
+// If the most recent copy is corrupt, the other copy is used as a fallback
+func mostRecentBlock(first, second chunk, blockOf func(chunk) block) (uint, error) {
+	newest, backup := byAge(first, second, blockOf)
 
-	if !firstChunk.isValid() {
-		fmt.Println("First chunk invalid")
-		return false
+	newestErr := blockOf(newest).validate()
+	if newestErr == nil {
+		return newest.offset, nil
 	}
 
-	if !secondChunk.isValid() {
-		fmt.Println("Second chunk invalid")
+	backupErr := blockOf(backup).validate()
+	if backupErr == nil {
+		return backup.offset, nil
+	}
+
+	return 0, errors.Join(newestErr, backupErr)
+}
+
+// Picks the most recent valid small block and big block of the savefile,
+// each by its own save number and checksum
+func MostRecentBlocks(savefile []byte, profile game_profile.GameProfile) (SaveBlocks, error) {
+	if uint(len(savefile)) != game_profile.SAVEFILE_SIZE {
+		return SaveBlocks{}, ErrInvalidSize
+	}
+
+	first := getChunk(savefile, profile, 0)
+	second := getChunk(savefile, profile, profile.SecondChunkOffset)
+
+	smallChunk, err := mostRecentBlock(first, second, smallBlockOf)
+	if err != nil {
+		return SaveBlocks{}, err
+	}
+
+	bigChunk, err := mostRecentBlock(first, second, bigBlockOf)
+	if err != nil {
+		return SaveBlocks{}, err
+	}
+
+	return SaveBlocks{smallChunk, bigChunk}, nil
+}
+
+// recomputes the CRC16 of the block and stores it in its footer
+func (b block) updateChecksum(savefile []byte) {
+	checksumAddr := b.offset + uint(len(b.blockData)) + b.footerSize - 2
+	binary.LittleEndian.PutUint16(savefile[checksumAddr:checksumAddr+2], crc16_ccitt(b.blockData))
+}
+
+// Recomputes the footer checksums of the small and big block in `blocks`.
+// Must be called after editing a block so the game accepts the file
+func UpdateChecksums(savefile []byte, profile game_profile.GameProfile, blocks SaveBlocks) error {
+	if uint(len(savefile)) != game_profile.SAVEFILE_SIZE {
+		return ErrInvalidSize
+	}
+
+	for _, chunkOffset := range []uint{blocks.SmallChunk, blocks.BigChunk} {
+		if chunkOffset != 0 && chunkOffset != profile.SecondChunkOffset {
+			return fmt.Errorf("invalid chunk offset 0x%x", chunkOffset)
+		}
+	}
+
+	getChunk(savefile, profile, blocks.SmallChunk).smallBlock.updateChecksum(savefile)
+	getChunk(savefile, profile, blocks.BigChunk).bigBlock.updateChecksum(savefile)
+
+	return nil
+}
+
+// how closely a savefile matches the layout of a game profile
+type Match struct {
+	Profile     game_profile.GameProfile
+	ChunkOffset uint    // offset of the chunk that matched best
+	Confidence  float64 // fraction of footer checks that passed, from 0 to 1
+	// blocks of the best chunk whose checksum matched. Block sizes alone
+	// are shared by too many layouts to identify a game
+	ValidChecksums uint
+}
+
+// footer checks for a single block: the block size stored in the
+// footer must match the layout, and so must the checksum
+func (b block) matchScore(expectedSize uint) (score int, checksumValid bool) {
+	if uint(b.footer.BlockSize) == expectedSize {
+		score++
+	}
+
+	if b.validate() == nil {
+		score++
+		checksumValid = true
+	}
+
+	return score, checksumValid
+}
+
+// Probes the footer locations and block sizes of `profile` in both chunks.
+// Only the best chunk counts, since the second chunk is blank until the game
+// has been saved twice
+func MatchProfile(savefile []byte, profile game_profile.GameProfile) Match {
+	res := Match{Profile: profile}
+	if uint(len(savefile)) != game_profile.SAVEFILE_SIZE {
+		return res
+	}
+
+	for _, offset := range []uint{0, profile.SecondChunkOffset} {
+		c := getChunk(savefile, profile, offset)
+		smallScore, smallValid := c.smallBlock.matchScore(profile.SmallBlockSize)
+		bigScore, bigValid := c.bigBlock.matchScore(profile.BigBlockSize)
+		confidence := float64(smallScore+bigScore) / 4
+
+		checksums := uint(0)
+		for _, valid := range []bool{smallValid, bigValid} {
+			if valid {
+				checksums++
+			}
+		}
+
+		if confidence > res.Confidence || (confidence == res.Confidence && checksums > res.ValidChecksums) {
+			res.ChunkOffset = offset
+			res.Confidence = confidence
+			res.ValidChecksums = checksums
+		}
+	}
+
+	return res
+}
+
+// validates the given .sav file against the layout of `profile`
+func Validate(savefile []byte, profile game_profile.GameProfile) bool {
+	if uint(len(savefile)) != game_profile.SAVEFILE_SIZE {
 		return false
 	}
 
-	return true
+	firstChunk := getChunk(savefile, profile, 0)
+	secondChunk := getChunk(savefile, profile, profile.SecondChunkOffset)
+
+	return firstChunk.isValid() && secondChunk.isValid()
 }
@@ -0,0 +1,217 @@
+package validator
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/game_profile"
+)
+
+const savefileSize = game_profile.SAVEFILE_SIZE
+
+var platinum = game_profile.Platinum
+var secondChunkOffset = platinum.SecondChunkOffset
+
+// writes a footer with the given save number and a valid checksum
+// for each block of the chunk at `offset`
+func writeChunkProfile(savefile []byte, profile game_profile.GameProfile, offset uint, saveNumber uint32) {
+	c := getChunk(savefile, profile, offset)
+
+	sizes := []uint{profile.SmallBlockSize, profile.BigBlockSize}
+
+	for i, b := range []block{c.smallBlock, c.bigBlock} {
+		saveNumberAddr := b.offset + uint(len(b.blockData)) + b.footerSize - 0x10
+		binary.LittleEndian.PutUint32(savefile[saveNumberAddr:saveNumberAddr+4], saveNumber)
+		binary.LittleEndian.PutUint32(savefile[saveNumberAddr+4:saveNumberAddr+8], uint32(sizes[i]))
+		b.updateChecksum(savefile)
+	}
+}
+
+func writeChunk(savefile []byte, offset uint, saveNumber uint32) {
+	writeChunkProfile(savefile, platinum, offset, saveNumber)
+}
+
+// overwrites the save number of a single block and fixes its checksum
+func setSaveNumber(savefile []byte, b block, saveNumber uint32) {
+	saveNumberAddr := b.offset + uint(len(b.blockData)) + b.footerSize - 0x10
+	binary.LittleEndian.PutUint32(savefile[saveNumberAddr:saveNumberAddr+4], saveNumber)
+	b.updateChecksum(savefile)
+}
+
+func TestMostRecentBlocks(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	writeChunk(savefile, 0, 1)
+	writeChunk(savefile, secondChunkOffset, 2)
+
+	blocks, err := MostRecentBlocks(savefile, platinum)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if blocks != (SaveBlocks{secondChunkOffset, secondChunkOffset}) {
+		t.Fatalf("expected both blocks from 0x%x, got %+v", secondChunkOffset, blocks)
+	}
+}
+
+func TestMostRecentBlocksDisagree(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	writeChunk(savefile, 0, 3)
+	writeChunk(savefile, secondChunkOffset, 4)
+
+	// the boxes were last changed in the first chunk, which makes its big
+	// block newer than the one next to the newest small block
+	setSaveNumber(savefile, getChunk(savefile, platinum, 0).bigBlock, 5)
+
+	blocks, err := MostRecentBlocks(savefile, platinum)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if blocks != (SaveBlocks{secondChunkOffset, 0}) {
+		t.Fatalf("expected the small block from 0x%x and big block from 0x0, got %+v", secondChunkOffset, blocks)
+	}
+}
+
+func TestMostRecentBlocksFallback(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	writeChunk(savefile, 0, 1)
+	writeChunk(savefile, secondChunkOffset, 2)
+
+	// corrupt the newest small block
+	savefile[secondChunkOffset+0x10] ^= 0xFF
+
+	blocks, err := MostRecentBlocks(savefile, platinum)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	// the newest big block is still valid
+	if blocks != (SaveBlocks{0, secondChunkOffset}) {
+		t.Fatalf("expected the small block from 0x0 and big block from 0x%x, got %+v", secondChunkOffset, blocks)
+	}
+}
+
+func TestMostRecentBlocksBothInvalid(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	writeChunk(savefile, 0, 1)
+	writeChunk(savefile, secondChunkOffset, 2)
+
+	savefile[0x10] ^= 0xFF
+	savefile[secondChunkOffset+0x10] ^= 0xFF
+
+	if _, err := MostRecentBlocks(savefile, platinum); err == nil {
+		t.Fatal("Error not thrown when both chunks are invalid")
+	}
+}
+
+func TestMostRecentBlocksInvalidSize(t *testing.T) {
+	if _, err := MostRecentBlocks(make([]byte, 1024), platinum); !errors.Is(err, ErrInvalidSize) {
+		t.Fatal("Error not thrown for invalid savefile size")
+	}
+}
+
+func TestMostRecentBlocksChecksumError(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	writeChunk(savefile, 0, 1)
+	savefile[0x10] ^= 0xFF
+
+	_, err := MostRecentBlocks(savefile, platinum)
+	var checksumErr *ChecksumError
+	if !errors.As(err, &checksumErr) {
+		t.Fatalf("expected a ChecksumError, got %v", err)
+	}
+}
+
+func TestUpdateChecksums(t *testing.T) {
+	savefile := make([]byte, savefileSize)
+	writeChunk(savefile, 0, 1)
+
+	// simulate an edit to the small block
+	savefile[0x10] ^= 0xFF
+	if getChunk(savefile, platinum, 0).isValid() {
+		t.Fatal("chunk should be invalid after editing")
+	}
+
+	if err := UpdateChecksums(savefile, platinum, SaveBlocks{}); err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if err := getChunk(savefile, platinum, 0).validate(); err != nil {
+		t.Fatal("chunk still invalid after updating checksums: ", err)
+	}
+}
+
+func TestMostRecentBlocksHGSS(t *testing.T) {
+	hgss := game_profile.HeartGoldSoulSilver
+	savefile := make([]byte, savefileSize)
+	writeChunkProfile(savefile, hgss, 0, 5)
+	writeChunkProfile(savefile, hgss, hgss.SecondChunkOffset, 4)
+
+	blocks, err := MostRecentBlocks(savefile, hgss)
+	if err != nil {
+		t.Fatal("Unexpected error ", err)
+	}
+
+	if blocks != (SaveBlocks{}) {
+		t.Fatalf("expected both blocks from 0x0, got %+v", blocks)
+	}
+
+	if !Validate(savefile, hgss) {
+		t.Fatal("HGSS savefile should be valid")
+	}
+
+	// the platinum footers are in a different place
+	if Validate(savefile, platinum) {
+		t.Fatal("HGSS savefile should not validate as platinum")
+	}
+}
+
+func TestGetFooter(t *testing.T) {
+	buf := []byte{
+		0x01, 0x00, 0x00, 0x00,
+		0x02, 0x00, 0x00, 0x00,
+		0x2C, 0xCF, 0x00, 0x00,
+		0x03, 0x00, 0x00, 0x00,
+		0x04, 0x00,
+		0x05, 0x00,
+	}
+
+	f := getFooter(buf)
+	expected := Footer{1, 2, 0xCF2C, 3, 4, 5}
+	if f != expected {
+		t.Fatalf("expected %s, got %s", expected, f)
+	}
+
+	// HGSS footers have no identifier
+	f = getFooter(buf[4:])
+	expected.Identifier = 0
+	if f != expected {
+		t.Fatalf("expected %s, got %s", expected, f)
+	}
+}
+
+func TestMatchProfile(t *testing.T) {
+	for _, profile := range game_profile.Profiles {
+		savefile := make([]byte, savefileSize)
+		writeChunkProfile(savefile, profile, profile.SecondChunkOffset, 1)
+
+		for _, other := range game_profile.Profiles {
+			match := MatchProfile(savefile, other)
+
+			if other.Name == profile.Name {
+				if match.Confidence != 1 || match.ValidChecksums != 2 || match.ChunkOffset != profile.SecondChunkOffset {
+					t.Fatalf("%s: expected a full match on the second chunk, got %+v", profile, match)
+				}
+			} else if match.Confidence == 1 {
+				t.Fatalf("%s savefile fully matched %s", profile, other)
+			}
+		}
+	}
+}
+
+func TestMatchProfileInvalidSize(t *testing.T) {
+	if match := MatchProfile(make([]byte, 1024), platinum); match.Confidence != 0 {
+		t.Fatalf("expected no confidence, got %f", match.Confidence)
+	}
+}
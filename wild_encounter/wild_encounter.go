@@ -0,0 +1,249 @@
+package wild_encounter
+
+import (
+	"slices"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/data"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/prng"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+type EncounterType uint8
+
+const (
+	// walking in grass or caves: the slot. Levels are fixed per slot
+	Grass EncounterType = iota
+	// Sweet Scent in grass or caves, which uses the same calls as Grass
+	SweetScent
+	// surfing: the slot, then the level
+	Surf
+	// fishing: a bite check against the rod's bite rate, the slot, then the level
+	OldRod
+	GoodRod
+	SuperRod
+)
+
+func (e EncounterType) String() string {
+	switch e {
+	case Grass:
+		return "Grass"
+	case SweetScent:
+		return "Sweet Scent"
+	case Surf:
+		return "Surf"
+	case OldRod:
+		return "Old Rod"
+	case GoodRod:
+		return "Good Rod"
+	case SuperRod:
+		return "Super Rod"
+	default:
+		return "Unknown"
+	}
+}
+
+// chance of each encounter slot out of 100
+var slotRates = map[EncounterType][]uint16{
+	Grass:      {20, 20, 10, 10, 10, 10, 5, 5, 4, 4, 1, 1},
+	SweetScent: {20, 20, 10, 10, 10, 10, 5, 5, 4, 4, 1, 1},
+	Surf:       {60, 30, 5, 4, 1},
+	OldRod:     {60, 30, 5, 4, 1},
+	GoodRod:    {40, 40, 15, 4, 1},
+	SuperRod:   {40, 40, 15, 4, 1},
+}
+
+// chance out of 100 that a fish bites, by rod
+var biteRates = map[EncounterType]uint16{
+	OldRod:   25,
+	GoodRod:  50,
+	SuperRod: 75,
+}
+
+func (e EncounterType) isFishing() bool {
+	_, ok := biteRates[e]
+	return ok
+}
+
+// the slot call is scaled down to 0-99 before being compared against the rates
+func (e EncounterType) slot(rand uint16) uint8 {
+	roll := rand / 656
+	for slot, rate := range slotRates[e] {
+		if roll < rate {
+			return uint8(slot)
+		}
+		roll -= rate
+	}
+	return uint8(len(slotRates[e]) - 1)
+}
+
+// ability of the party lead affecting the encounter
+type Lead uint8
+
+const (
+	NoLead Lead = iota
+	// passes its nature on half of the time
+	Synchronize
+	// makes the pokemon the opposite gender of the lead two thirds of the time
+	CuteCharm
+)
+
+func (l Lead) String() string {
+	switch l {
+	case Synchronize:
+		return "Synchronize"
+	case CuteCharm:
+		return "Cute Charm"
+	default:
+		return "None"
+	}
+}
+
+// Frames follow PokeFinder's Method J convention. The encounter check made
+// while walking or surfing is not part of a frame, so a Grass or Surf frame
+// starts at the slot call; fishing frames start at the bite check.
+// PokeFinder counts advances from 0, so frame N is its advance N - 1
+type Config struct {
+	InitialSeed uint32
+	// frames to predict, both inclusive. Frame 1 starts at InitialSeed
+	MinFrame uint32
+	MaxFrame uint32
+
+	Encounter EncounterType
+	Lead      Lead
+	// nature of a Synchronize lead
+	LeadNature rom_reader.Nature
+	// gender of a Cute Charm lead
+	LeadGender rom_reader.Gender
+
+	// gender ratio of the species being predicted
+	GenderRatio data.GenderRatio
+	TID         uint16
+	SID         uint16
+}
+
+type Encounter struct {
+	Frame uint32
+	// PRNG state right before the encounter's first call
+	Seed   uint32
+	Slot   uint8
+	Nature rom_reader.Nature
+	PID    uint32
+	IVs    rom_reader.Stats
+	Shiny  bool
+	Gender rom_reader.Gender
+	// the lead's ability decided the nature or gender
+	LeadApplied bool
+}
+
+// Criteria an encounter must meet to be listed. Empty slices accept anything
+type Filter struct {
+	Slots     []uint8
+	Natures   []rom_reader.Nature
+	Genders   []rom_reader.Gender
+	ShinyOnly bool
+	// every IV must be at least this high
+	MinIVs rom_reader.Stats
+}
+
+func (f Filter) Matches(e Encounter) bool {
+	if len(f.Slots) > 0 && !slices.Contains(f.Slots, e.Slot) {
+		return false
+	}
+
+	if len(f.Natures) > 0 && !slices.Contains(f.Natures, e.Nature) {
+		return false
+	}
+
+	if len(f.Genders) > 0 && !slices.Contains(f.Genders, e.Gender) {
+		return false
+	}
+
+	if f.ShinyOnly && !e.Shiny {
+		return false
+	}
+
+	for _, stat := range rom_reader.AllStats {
+		if e.IVs.Get(stat) < f.MinIVs.Get(stat) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Cute Charm only works on species that can be either gender
+func hasBothGenders(ratio data.GenderRatio) bool {
+	return ratio != data.RatioMaleOnly && ratio != data.RatioFemaleOnly && ratio != data.RatioGenderless
+}
+
+// Generates the encounter the game would produce from PRNG state `seed`
+// with Method J. ok is false if no fish bites on this frame
+func (c Config) generate(frame uint32, seed uint32) (e Encounter, ok bool) {
+	lcg := prng.LCG{State: seed}
+	e = Encounter{Frame: frame, Seed: seed}
+
+	if c.Encounter.isFishing() && lcg.Next()/656 >= biteRates[c.Encounter] {
+		return Encounter{}, false
+	}
+
+	e.Slot = c.Encounter.slot(lcg.Next())
+	if c.Encounter != Grass && c.Encounter != SweetScent {
+		lcg.Next()
+	}
+
+	if c.Lead == CuteCharm && hasBothGenders(c.GenderRatio) && lcg.Next()/0x5556 != 0 {
+		// the PID is built from the nature instead of being rolled, with
+		// a low byte below the ratio for a female and above it for a male
+		e.PID = uint32(lcg.Next() / 0xA3E)
+		if c.LeadGender == rom_reader.Female {
+			e.PID += 25 * (uint32(c.GenderRatio)/25 + 1)
+		}
+		e.LeadApplied = true
+	} else {
+		var nature uint32
+		if c.Lead == Synchronize && lcg.Next()>>15 == 0 {
+			nature = uint32(c.LeadNature.Index)
+			e.LeadApplied = true
+		} else {
+			nature = uint32(lcg.Next() / 0xA3E)
+		}
+
+		// PIDs are rerolled until they have the nature
+		for e.PID = uint32(lcg.Next()) | uint32(lcg.Next())<<16; e.PID%25 != nature; {
+			e.PID = uint32(lcg.Next()) | uint32(lcg.Next())<<16
+		}
+	}
+
+	iv1, iv2 := lcg.Next()&0x7FFF, lcg.Next()&0x7FFF
+	e.IVs = rom_reader.UnpackIVs(uint32(iv1) | uint32(iv2)<<15)
+	e.Nature = rom_reader.NatureFromPID(e.PID)
+	e.Shiny = rom_reader.IsShiny(e.PID, c.TID, c.SID)
+	e.Gender = rom_reader.GenderFromPID(e.PID, c.GenderRatio)
+
+	return e, true
+}
+
+// Predicts the Method J wild encounter of every frame from c.MinFrame to
+// c.MaxFrame and returns the ones matching `filter`, in frame order.
+// Fishing frames where nothing bites are left out
+func Predict(c Config, filter Filter) []Encounter {
+	var res []Encounter
+	minFrame := max(c.MinFrame, 1)
+
+	lcg := prng.LCG{State: c.InitialSeed}
+	lcg.Advance(uint64(minFrame - 1))
+
+	for frame := minFrame; frame <= c.MaxFrame; frame++ {
+		if e, ok := c.generate(frame, lcg.State); ok && filter.Matches(e) {
+			res = append(res, e)
+		}
+
+		// stop before frame wraps around when MaxFrame is the largest frame
+		if frame == c.MaxFrame {
+			break
+		}
+		lcg.Next()
+	}
+
+	return res
+}
@@ -0,0 +1,264 @@
+package wild_encounter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/dingdongg/pkmn-platinum-rom-parser/data"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/pidiv"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/prng"
+	"github.com/dingdongg/pkmn-platinum-rom-parser/rom_reader"
+)
+
+// 2009-03-15 10:20:30 with a delay of 600
+const initialSeed = 0x5F0A0261
+
+func config(encounter EncounterType, lead Lead) Config {
+	return Config{
+		InitialSeed: initialSeed,
+		MinFrame:    1,
+		MaxFrame:    200,
+		Encounter:   encounter,
+		Lead:        lead,
+		LeadNature:  rom_reader.Natures[13],
+		LeadGender:  rom_reader.Male,
+		GenderRatio: data.RatioEven,
+		TID:         12345,
+		SID:         54321,
+	}
+}
+
+// state before the call deciding the nature, which pidiv reports as the
+// seed. A failed Synchronize check is one more call before the nature call
+func natureSeed(e Encounter, encounter EncounterType, lead Lead) uint32 {
+	calls := map[EncounterType]uint64{Grass: 1, SweetScent: 1, Surf: 2, OldRod: 3}[encounter]
+	if lead == Synchronize && !e.LeadApplied {
+		calls++
+	}
+	lcg := prng.LCG{State: e.Seed}
+	lcg.Advance(calls)
+	return lcg.State
+}
+
+func TestPredictMatchesPIDIV(t *testing.T) {
+	for _, encounter := range []EncounterType{Grass, SweetScent, Surf, OldRod} {
+		for _, lead := range []Lead{NoLead, Synchronize} {
+			encounters := Predict(config(encounter, lead), Filter{})
+			if len(encounters) < 20 || (!encounter.isFishing() && len(encounters) != 200) {
+				t.Fatalf("%s: expected 200 frames, got %d", encounter, len(encounters))
+			}
+
+			for _, e := range encounters[:20] {
+				seed := natureSeed(e, encounter, lead)
+				found := false
+				for _, m := range pidiv.Find(e.PID, e.IVs) {
+					if m.Method == pidiv.MethodJ && m.Seed == seed && m.Synchronize == e.LeadApplied {
+						found = true
+					}
+				}
+
+				if !found {
+					t.Fatalf("%s %s frame %d: pid 0x%08X not found as Method J from 0x%08X", encounter, lead, e.Frame, e.PID, seed)
+				}
+			}
+		}
+	}
+}
+
+// Frames of initial seed 0x5F0A0261 (2009-03-15 10:20:30, delay 600) with no
+// lead. They were computed with a standalone script following the steps of
+// PokeFinder's Method J generator, not captured from PokeFinder itself, so
+// they pin the convention down rather than prove it. The calls of each frame:
+//
+//	grass frame 1:     0xFC02 slot 10, 0xED69 nature 23
+//	surf frame 1:      0xFC02 slot 3, 0xED69 level, 0x87A1 nature 13
+//	super rod frame 1: 0xFC02 / 656 = 98, no bite
+//	super rod frame 3: 0x87A1 / 656 = 52, bite, 0xB4CF slot 1
+var referenceFrames = []struct {
+	encounter EncounterType
+	frame     uint32
+	slot      uint8
+	nature    uint8
+	pid       uint32
+	ivs       rom_reader.Stats
+}{
+	{Grass, 1, 10, 23, 0x906E08A1, rom_reader.Stats{Hp: 3, Attack: 10, Defense: 23, SpAttack: 30, SpDefense: 21, Speed: 20}},
+	{Grass, 2, 8, 13, 0x20BDED00, rom_reader.Stats{Hp: 29, Attack: 30, Defense: 0, SpAttack: 6, SpDefense: 27, Speed: 5}},
+	{Surf, 1, 3, 13, 0x20BDED00, rom_reader.Stats{Hp: 29, Attack: 30, Defense: 0, SpAttack: 6, SpDefense: 27, Speed: 5}},
+	{Surf, 4, 1, 18, 0x5AB89474, rom_reader.Stats{Hp: 28, Attack: 5, Defense: 18, SpAttack: 8, SpDefense: 27, Speed: 0}},
+	{SuperRod, 3, 1, 18, 0x5AB89474, rom_reader.Stats{Hp: 28, Attack: 5, Defense: 18, SpAttack: 8, SpDefense: 27, Speed: 0}},
+	{SuperRod, 7, 2, 7, 0xA8C52906, rom_reader.Stats{Hp: 24, Attack: 21, Defense: 6, SpAttack: 22, SpDefense: 18, Speed: 31}},
+}
+
+func TestPredictReferenceFrames(t *testing.T) {
+	for _, ref := range referenceFrames {
+		c := config(ref.encounter, NoLead)
+		c.MinFrame, c.MaxFrame = ref.frame, ref.frame
+
+		encounters := Predict(c, Filter{})
+		if len(encounters) != 1 {
+			t.Fatalf("%s frame %d: expected an encounter, got %d", ref.encounter, ref.frame, len(encounters))
+		}
+
+		e := encounters[0]
+		if e.Slot != ref.slot || e.Nature.Index != ref.nature || e.PID != ref.pid || e.IVs != ref.ivs {
+			t.Fatalf("%s frame %d: expected slot %d nature %d pid 0x%08X ivs %+v, got %+v",
+				ref.encounter, ref.frame, ref.slot, ref.nature, ref.pid, ref.ivs, e)
+		}
+	}
+}
+
+func TestPredictNoBite(t *testing.T) {
+	c := config(SuperRod, NoLead)
+	c.MaxFrame = 7
+
+	// nothing bites on frames 1 and 2
+	encounters := Predict(c, Filter{})
+	if len(encounters) != 5 || encounters[0].Frame != 3 {
+		t.Fatalf("expected frames 3 to 7, got %+v", encounters)
+	}
+}
+
+func TestPredictFrames(t *testing.T) {
+	c := config(SweetScent, NoLead)
+	all := Predict(c, Filter{})
+
+	c.MinFrame, c.MaxFrame = 50, 60
+	window := Predict(c, Filter{})
+	if len(window) != 11 {
+		t.Fatalf("expected 11 frames, got %d", len(window))
+	}
+
+	for i, e := range window {
+		if e != all[49+i] {
+			t.Fatalf("frame %d differs when starting at frame 50: %+v vs %+v", e.Frame, e, all[49+i])
+		}
+	}
+
+	lcg := prng.LCG{State: initialSeed}
+	lcg.Next()
+	if all[0].Seed != initialSeed || all[1].Seed != lcg.State {
+		t.Fatalf("frame 1 should start at the initial seed, got 0x%08X", all[0].Seed)
+	}
+}
+
+func TestPredictLastFrame(t *testing.T) {
+	c := config(SweetScent, NoLead)
+	c.MinFrame, c.MaxFrame = math.MaxUint32-2, math.MaxUint32
+
+	encounters := Predict(c, Filter{})
+	if len(encounters) != 3 || encounters[2].Frame != math.MaxUint32 {
+		t.Fatalf("expected the last 3 frames, got %d encounters", len(encounters))
+	}
+}
+
+func TestSynchronize(t *testing.T) {
+	applied := 0
+	for _, e := range Predict(config(SweetScent, Synchronize), Filter{}) {
+		if e.LeadApplied {
+			applied++
+			if e.Nature != rom_reader.Natures[13] {
+				t.Fatalf("frame %d: synchronized nature %s, expected %s", e.Frame, e.Nature, rom_reader.Natures[13])
+			}
+		}
+	}
+
+	if applied == 0 || applied == 200 {
+		t.Fatalf("Synchronize applied on %d of 200 frames", applied)
+	}
+}
+
+func TestCuteCharm(t *testing.T) {
+	for _, leadGender := range []rom_reader.Gender{rom_reader.Male, rom_reader.Female} {
+		c := config(SweetScent, CuteCharm)
+		c.LeadGender = leadGender
+
+		applied := 0
+		for _, e := range Predict(c, Filter{}) {
+			if !e.LeadApplied {
+				continue
+			}
+			applied++
+
+			if e.Gender == leadGender {
+				t.Fatalf("frame %d: Cute Charm gave the lead's gender %s", e.Frame, e.Gender)
+			}
+
+			if e.PID>>8 != 0 {
+				t.Fatalf("frame %d: Cute Charm PID 0x%08X should fit in a byte", e.Frame, e.PID)
+			}
+		}
+
+		if applied == 0 {
+			t.Fatalf("Cute Charm never applied")
+		}
+	}
+
+	// genderless pokemon ignore Cute Charm
+	c := config(SweetScent, CuteCharm)
+	c.GenderRatio = data.RatioGenderless
+	noLead := config(SweetScent, NoLead)
+	noLead.GenderRatio = data.RatioGenderless
+
+	expected := Predict(noLead, Filter{})
+	for i, e := range Predict(c, Filter{}) {
+		if e != expected[i] {
+			t.Fatalf("frame %d: genderless encounter differs with Cute Charm", e.Frame)
+		}
+	}
+}
+
+func TestSlots(t *testing.T) {
+	tests := []struct {
+		encounter EncounterType
+		rand      uint16
+		expected  uint8
+	}{
+		{SweetScent, 0, 0},
+		{SweetScent, 19*656 + 655, 0},
+		{SweetScent, 20 * 656, 1},
+		{SweetScent, 98 * 656, 10},
+		{SweetScent, 0xFFFF, 11},
+		{Surf, 59*656 + 655, 0},
+		{Surf, 60 * 656, 1},
+		{Surf, 0xFFFF, 4},
+		{GoodRod, 40 * 656, 1},
+		{GoodRod, 80 * 656, 2},
+		{SuperRod, 95 * 656, 3},
+	}
+
+	for _, test := range tests {
+		if actual := test.encounter.slot(test.rand); actual != test.expected {
+			t.Fatalf("%s 0x%04X: expected slot %d, got %d", test.encounter, test.rand, test.expected, actual)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	adamant := rom_reader.Natures[3]
+	filter := Filter{
+		Slots:   []uint8{0, 1},
+		Natures: []rom_reader.Nature{adamant},
+		Genders: []rom_reader.Gender{rom_reader.Female},
+		MinIVs:  rom_reader.Stats{Attack: 20},
+	}
+
+	c := config(SweetScent, NoLead)
+	c.MaxFrame = 20000
+	results := Predict(c, filter)
+	if len(results) == 0 {
+		t.Fatalf("expected at least one match in 20000 frames")
+	}
+
+	for _, e := range results {
+		if e.Slot > 1 || e.Nature != adamant || e.Gender != rom_reader.Female || e.IVs.Attack < 20 {
+			t.Fatalf("frame %d does not match the filter: %+v", e.Frame, e)
+		}
+	}
+
+	for _, e := range Predict(c, Filter{ShinyOnly: true}) {
+		if !e.Shiny {
+			t.Fatalf("frame %d is not shiny", e.Frame)
+		}
+	}
+}